@@ -8,6 +8,7 @@ import (
 	ft232h2 "github.com/ardnew/ft232h"
 	"github.com/rs/zerolog"
 	"github.com/yunginnanet/ftdi-ads1256/pkg/ads1256"
+	"github.com/yunginnanet/ftdi-ads1256/pkg/filter"
 	"github.com/yunginnanet/ftdi-ads1256/pkg/ft232h"
 	"os"
 	"strconv"
@@ -83,23 +84,27 @@ var intToChannel = map[int]ads1256.Channel{
 	7: ads1256.CH_AIN7,
 }
 
-func flags() (ftindex int, cs uint, drdy uint, pwdn uint, channels []ads1256.ChannelPair) {
+func flags() (ftindex int, cs uint, drdy uint, pwdn uint, channels []ads1256.ChannelPair, calDir string, period time.Duration, filterSpec string, rate float64) {
 	fti := flag.Int("FT232H", 0, "FT232H Index")
 	csi := flag.Uint("CS", 0x10, "Chip Select (SPI, Digital)")
 	dri := flag.Uint("DRDY", 0x01, "Data Ready (GPIO)")
 	pwi := flag.Uint("PWDN", 0x40, "Power Down (GPIO)")
 	channelsStr := flag.String("channels", "0,1,2,3,4,5,6,7", "Comma-separated list of channels to scan")
 	pinCheck := flag.Bool("pin-check", false, "Check GPIO pin validity and debug positions, then exit")
+	calDirStr := flag.String("cal-dir", "", "Directory to save and restore calibration profiles in (disabled if empty)")
+	periodDur := flag.Duration("period", 0, "Start a scan pass every period on a fixed timeline, skipping missed passes (0 scans back to back)")
+	filterStr := flag.String("filter", "", "Comma-separated filter stages applied per channel, e.g. \"median:3,notch:50,lp:10\" (see filter.Parse; lp, hp, bp and notch need -period)")
+	rateHz := flag.Float64("rate", 0, "Resample all channels to frames at this rate in Hz, sharing one timestamp (0 disables, needs -period)")
 	flag.Parse()
 	if !*pinCheck {
 		var err error
 		if channels, err = strToChannelPairs(*channelsStr); err != nil {
 			log.Fatal().Err(err).Msg("failed to parse channel numbers")
 		}
-		return *fti, *csi, *dri, *pwi, channels
+		return *fti, *csi, *dri, *pwi, channels, *calDirStr, *periodDur, *filterStr, *rateHz
 	}
 	pCheck(csi, dri, pwi)
-	return 0, 0, 0, 0, nil
+	return 0, 0, 0, 0, nil, "", 0, "", 0
 }
 
 func checkPin(serial *ft232h.FT232H, pin ft232h2.CPin, old bool) bool {
@@ -160,8 +165,31 @@ func setGPIOPins(serial *ft232h.FT232H, drdy, cs, pwdn uint) {
 	}
 }
 
+func saveProfile(adc *ads1256.ADS1256, dir, serial string) {
+	profile, err := adc.CalibrationProfile(serial)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to read calibration profile")
+		return
+	}
+	if err = ads1256.SaveCalibrationProfile(dir, profile); err != nil {
+		log.Warn().Err(err).Msg("failed to save calibration profile")
+		return
+	}
+	log.Info().Str("dir", dir).Str("serial", serial).Msg("saved calibration profile")
+}
+
 func main() {
-	ftindex, cs, drdy, pwdn, channels := flags()
+	ftindex, cs, drdy, pwdn, channels, calDir, period, filterSpec, rate := flags()
+
+	var noiseOpts ads1256.NoiseOptions
+	var noiseOut string
+	noise := flag.Arg(0) == "noise"
+	switch {
+	case noise:
+		noiseOpts, noiseOut = noiseFlags(flag.Args()[1:])
+	case flag.NArg() > 0:
+		log.Fatal().Strs("args", flag.Args()).Msg("unknown subcommand, expected noise")
+	}
 
 	serial, err := ft232h.ConnectFT232h(ft232h.ByIndex(ftindex))
 	if err != nil {
@@ -241,6 +269,20 @@ func main() {
 	cfg.BufferEn = true
 	cfg.DataRate = ads1256.DRATE_DR_2000_SPS
 	cfg.PGA = ads1256.ADCON_PGA_16
+	cfg.SCLKHz = float64(spiCfg.Clock)
+
+	if calDir != "" {
+		profile, perr := ads1256.LoadCalibrationProfile(calDir, serial.Info().Serial, cfg)
+		switch {
+		case perr == nil:
+			log.Info().Time("taken", profile.Taken).Msg("restoring calibration profile")
+			cfg.Calibration = &profile
+		case errors.Is(perr, os.ErrNotExist):
+			log.Info().Str("dir", calDir).Msg("no calibration profile found, will self-calibrate")
+		default:
+			log.Warn().Err(perr).Msg("refusing calibration profile, will self-calibrate")
+		}
+	}
 
 	log.Debug().Any("config", cfg).Msg("initializing ADS1256")
 	if err = adc.Initialize(cfg); err != nil {
@@ -249,6 +291,20 @@ func main() {
 
 	log.Info().Msg("initialized ADS1256")
 
+	if rate, rerr := adc.DataRateInfo(cfg.DataRate); rerr == nil {
+		log.Info().Float64("sps", rate.SPS).Dur("settling", rate.Settling).
+			Bool("rejects50Hz", rate.Rejects(50)).Bool("rejects60Hz", rate.Rejects(60)).Msg("data rate")
+	}
+
+	if calDir != "" && cfg.Calibration == nil {
+		saveProfile(adc, calDir, serial.Info().Serial)
+	}
+
+	if noise {
+		characterizeNoise(ctx, adc, cfg, serial.Info().Serial, channels, noiseOpts, noiseOut)
+		return
+	}
+
 	time.Sleep(200 * time.Millisecond)
 
 	go func() {
@@ -257,17 +313,65 @@ func main() {
 		cancel()
 	}()
 
-	cb := func(chPair ads1256.ChannelPair, code int32) {
-		log.Info().Int32("code", code).Any("chPair", chPair).Msg("data callback")
+	onSample := func(s ads1256.Sample) {
+		log.Info().Uint64("seq", s.Seq).Uint64("chanSeq", s.ChanSeq).Uint64("cycle", s.Cycle).
+			Int32("code", s.Code).Float64("volts", s.Value.Volts).Bool("inRange", s.Value.InRange()).
+			Any("chPair", s.Pair).Msg("sample")
 	}
 
 	var chScan *ads1256.ChannelScan
 
-	if chScan, err = adc.ScanChannelsContinuously(ctx, 0, cb, channels...); err != nil {
+	onMiss := func(m ads1256.DeadlineMiss) {
+		log.Warn().Uint64("cycle", m.Cycle).Dur("late", m.Late).Uint64("skipped", m.Skipped).Msg("missed scan deadline")
+	}
+
+	opts := ads1256.ScanOptions{Mode: ads1256.SCAN_RDATAC, OnSample: onSample, Period: period, OnMiss: onMiss}
+
+	// Each channel is sampled once per scan pass. Scanning back to back, mux settling and USB
+	// round trips make the per-channel rate lower than the data rate divided among the channels
+	// by an amount only known once the scan runs, so frequency based stages need -period.
+	var fs float64
+	if period > 0 {
+		fs = 1 / period.Seconds()
+	}
+
+	factory, filteredFS, err := filter.Parse(filterSpec, fs)
+	if err != nil {
+		if period == 0 {
+			log.Fatal().Err(err).Msg("failed to parse filter (lp, hp, bp and notch need -period)")
+		}
+		log.Fatal().Err(err).Msg("failed to parse filter")
+	}
+
+	if rate > 0 {
+		if period == 0 {
+			log.Fatal().Float64("rate", rate).Msg("resampling needs -period, the channel rate of a back-to-back scan is not known in advance")
+		}
+		resampler, rerr := filter.NewResampler(rate, filteredFS, channels...)
+		if rerr != nil {
+			log.Fatal().Err(rerr).Msg("failed to set up resampling")
+		}
+		log.Info().Float64("rate", rate).Float64("fs", filteredFS).Msg("resampling")
+		opts.OnSample = resampler.OnSample(func(f filter.Frame) {
+			for _, s := range f.Samples {
+				onSample(s)
+			}
+		})
+	}
+
+	if factory != nil {
+		log.Info().Str("filter", filterSpec).Float64("fs", fs).Msg("filtering samples")
+		opts.OnSample = filter.NewPipeline(factory).OnSample(opts.OnSample)
+	}
+	if chScan, err = adc.StartScan(ctx, opts, channels...); err != nil {
 		log.Fatal().Err(err).Msg("failed to scan channels")
 	}
 
-	if err = chScan.Wait(ctx); err != nil {
+	// the scan stops by itself once ctx is cancelled
+	err = chScan.Wait(context.Background())
+	stats := chScan.Stats()
+	log.Info().Any("stats", stats).Msg(stats.String())
+	if err != nil {
 		if errc := adc.Close(); errc != nil {
 			log.Error().Err(errc).Msg("failed to close ADS1256")
 		}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yunginnanet/ftdi-ads1256/pkg/ads1256"
+)
+
+// noiseReport is the machine-readable result of a noise characterization, for tracking a
+// board over time.
+type noiseReport struct {
+	Serial   string                `json:"serial"`
+	Taken    time.Time             `json:"taken"`
+	VRef     float64               `json:"vref"`
+	ClockHz  float64               `json:"clock_hz"`
+	BufferEn bool                  `json:"buffer_en"`
+	Results  []ads1256.NoiseResult `json:"results"`
+}
+
+func parseGains(str string) ([]byte, error) {
+	var pgas []byte
+	for _, s := range strings.Split(str, ",") {
+		gain, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse comma separated PGA gains: %w", err)
+		}
+		pga := byte(ads1256.ADCON_PGA_1)
+		for (ads1256.ADCON{PGA: pga}).Gain() != gain {
+			if pga++; pga > ads1256.ADCON_PGA_64 {
+				return nil, fmt.Errorf("PGA gain %d is not a power of two from 1 to 64", gain)
+			}
+		}
+		pgas = append(pgas, pga)
+	}
+	return pgas, nil
+}
+
+func parseDataRates(str string) ([]byte, error) {
+	if str == "" {
+		return nil, nil
+	}
+	var codes []byte
+	for _, s := range strings.Split(str, ",") {
+		sps, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse comma separated data rates: %w", err)
+		}
+		info, err := ads1256.DataRateFor(sps, 0)
+		if err != nil {
+			return nil, err
+		}
+		if info.SPS != sps {
+			return nil, fmt.Errorf("no data rate of %g SPS, nearest is %g SPS", sps, info.SPS)
+		}
+		codes = append(codes, info.Code)
+	}
+	return codes, nil
+}
+
+// noiseFlags parses the flags of the noise subcommand, which characterizes noise (with the
+// inputs shorted) instead of scanning, from args.
+func noiseFlags(args []string) (opts ads1256.NoiseOptions, out string) {
+	fs := flag.NewFlagSet("noise", flag.ExitOnError)
+	samples := fs.Int("samples", 1000, "Samples per channel, PGA and data rate")
+	gainsStr := fs.String("pga", "1,2,4,8,16,32,64", "Comma-separated PGA gains to characterize noise at")
+	ratesStr := fs.String("sps", "", "Comma-separated data rates in SPS to characterize noise at (empty uses the configured rate)")
+	refPath := fs.String("ref", "", "JSON noise table to compare with, e.g. the datasheet figures for your buffer and VREF setup (empty means no comparison)")
+	outPath := fs.String("out", "-", "File to write the JSON noise report to (- for stdout)")
+	_ = fs.Parse(args)
+
+	var err error
+	opts.Samples = *samples
+	if opts.PGAs, err = parseGains(*gainsStr); err != nil {
+		log.Fatal().Err(err).Msg("failed to parse PGA gains")
+	}
+	if opts.DataRates, err = parseDataRates(*ratesStr); err != nil {
+		log.Fatal().Err(err).Msg("failed to parse data rates")
+	}
+	if *refPath != "" {
+		if opts.Reference, err = loadNoiseTable(*refPath); err != nil {
+			log.Fatal().Err(err).Msg("failed to load noise table")
+		}
+	}
+	return opts, *outPath
+}
+
+func loadNoiseTable(path string) (ads1256.NoiseTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var table ads1256.NoiseTable
+	if err = json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to parse noise table %s: %w", path, err)
+	}
+	return table, nil
+}
+
+func characterizeNoise(ctx context.Context, adc *ads1256.ADS1256, cfg ads1256.Config, serial string, channels []ads1256.ChannelPair, opts ads1256.NoiseOptions, outPath string) {
+	opts.OnResult = func(r ads1256.NoiseResult) {
+		log.Info().Float64("rms_uv", r.RMSMicrovolts).Float64("enob", r.EffectiveBits).Bool("no_noise", r.NoNoise).Msg(r.String())
+	}
+
+	var err error
+	report := noiseReport{
+		Serial:   serial,
+		Taken:    time.Now(),
+		VRef:     adc.VRef(),
+		ClockHz:  adc.Timing().ClockHz,
+		BufferEn: cfg.BufferEn,
+	}
+	if report.Results, err = adc.CharacterizeNoise(ctx, opts, channels...); err != nil {
+		log.Error().Err(err).Int("results", len(report.Results)).Msg("noise characterization did not complete")
+	}
+
+	out := os.Stdout
+	if outPath != "-" {
+		if out, err = os.Create(outPath); err != nil {
+			log.Fatal().Err(err).Msg("failed to create noise report")
+		}
+		defer func() {
+			if cerr := out.Close(); cerr != nil {
+				log.Error().Err(cerr).Msg("failed to write noise report")
+			}
+		}()
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(report); err != nil {
+		log.Error().Err(err).Msg("failed to write noise report")
+	}
+}
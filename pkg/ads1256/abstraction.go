@@ -1,25 +1,32 @@
 package ads1256
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 func (adc *ADS1256) setCSLow() error {
-	return adc.spi.SetCS(false)
+	defer adc.timeSPI(time.Now())
+	return transportError(adc.spi.SetCS(false))
 }
 func (adc *ADS1256) setCSHigh() error {
-	return adc.spi.SetCS(true)
+	defer adc.timeSPI(time.Now())
+	return transportError(adc.spi.SetCS(true))
 }
 
 func (adc *ADS1256) Write(p []byte) (int, error) {
+	defer adc.timeSPI(time.Now())
 	n, err := adc.spi.Write(p, false, false)
-	return int(n), err
+	return int(n), transportError(err)
 }
 func (adc *ADS1256) Read(p []byte) (int, error) {
+	defer adc.timeSPI(time.Now())
 	b, err := adc.spi.Read(uint(len(p)), false, false)
 	if err != nil {
-		return 0, err
+		return 0, transportError(err)
 	}
 	if len(p) < len(b) {
-		return 0, io.ErrShortBuffer
+		return 0, transportError(io.ErrShortBuffer)
 	}
-	return copy(p, b), err
+	return copy(p, b), nil
 }
@@ -0,0 +1,190 @@
+package ads1256
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+)
+
+// AcquireOptions configures [ADS1256.Acquire] and [ADS1256.AcquireChan].
+type AcquireOptions struct {
+	// Interval is the pause between passes over the channel pairs.
+	Interval time.Duration
+
+	// Mode selects how conversions are started:
+	//   - SCAN_RDATAC: every pair is converted on its own (MUX, SYNC, WAKEUP, DRDY, RDATA).
+	//     RDATAC itself is not used, because the lock is released between samples.
+	//   - SCAN_CYCLING: the next pair's conversion is started before the current result is read,
+	//     as in a [ChannelScan]. If anything else touches the ADC between two samples, the
+	//     pipeline is restarted and that sample costs a full conversion.
+	Mode ScanMode
+}
+
+// acquirer produces samples of a list of channel pairs one at a time, taking the ADC lock
+// for each acquisition only.
+type acquirer struct {
+	adc    *ADS1256
+	opts   AcquireOptions
+	pairs  []ChannelPair
+	i      int    // index of the next pair
+	cycle  uint64 // index of the current pass
+	primed bool   // SCAN_CYCLING: a conversion of pairs[i] has been started
+}
+
+func (adc *ADS1256) newAcquirer(opts AcquireOptions, pairs []ChannelPair) (*acquirer, error) {
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("%w to acquire", ErrNoChannels)
+	}
+	if opts.Mode != SCAN_RDATAC && opts.Mode != SCAN_CYCLING {
+		return nil, fmt.Errorf("%w: scan mode %d", ErrInvalidSetting, opts.Mode)
+	}
+	pairs, err := adc.resolvePairs(pairs)
+	if err != nil {
+		return nil, err
+	}
+	return &acquirer{adc: adc, opts: opts, pairs: pairs}, nil
+}
+
+// next acquires the next sample. Between passes it waits for opts.Interval, or until ctx is done.
+func (a *acquirer) next(ctx context.Context) (Sample, error) {
+	if a.i == 0 {
+		if a.cycle > 0 {
+			if err := sleepCtx(ctx, a.opts.Interval); err != nil {
+				return Sample{}, err
+			}
+		}
+		a.cycle++
+	}
+
+	adc := a.adc
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+
+	pair := a.pairs[a.i]
+
+	var (
+		code  int32
+		ready time.Time
+		err   error
+	)
+	if a.opts.Mode == SCAN_CYCLING {
+		code, ready, err = a.cycleStep(ctx, pair)
+	} else {
+		code, ready, err = adc.readPairAt(ctx, pair)
+	}
+	if err != nil {
+		a.primed = false
+		return Sample{}, err
+	}
+
+	a.i = (a.i + 1) % len(a.pairs)
+	return adc.newSample(pair, code, ready, a.cycle), nil
+}
+
+// cycleStep is one step of the fast channel cycling sequence (see cycleChannelPairs).
+// The caller must hold adc.mu.
+func (a *acquirer) cycleStep(ctx context.Context, pair ChannelPair) (int32, time.Time, error) {
+	adc := a.adc
+
+	mux := Mux{Pos: pair.Pos, Neg: pair.Neg}.Byte()
+	if !a.primed || adc.continuousMode.Load() || adc.regLW[REG_MUX] != mux || adc.settingsChanged(*pair.Settings) {
+		if err := adc.startConversion(pair); err != nil {
+			return 0, time.Time{}, err
+		}
+	}
+
+	if err := adc.waitDRDY(ctx); err != nil {
+		return 0, time.Time{}, err
+	}
+	ready := time.Now()
+
+	if err := adc.startConversion(a.pairs[(a.i+1)%len(a.pairs)]); err != nil {
+		return 0, time.Time{}, err
+	}
+	a.primed = true
+
+	code, err := adc.readDataByCommand()
+	return code, ready, err
+}
+
+// Acquire returns an iterator over samples of the given channel pairs, taken in order and
+// repeated until ctx is done or the consumer stops ranging.
+//
+// Unlike a [ChannelScan], nothing runs in the background: each sample is acquired when the
+// consumer asks for it, and the ADC lock is only held while it is being acquired, never while
+// the loop body runs. Other ADS1256 methods may be called between samples.
+//
+// Each pair is acquired with its own [ChannelPair.Settings], or with the settings from [Config].
+// An acquisition error is yielded once and ends the iteration; cancellation of ctx ends it
+// without an error.
+func (adc *ADS1256) Acquire(ctx context.Context, opts AcquireOptions, pairs ...ChannelPair) iter.Seq2[Sample, error] {
+	return func(yield func(Sample, error) bool) {
+		a, err := adc.newAcquirer(opts, pairs)
+		if err != nil {
+			yield(Sample{}, err)
+			return
+		}
+
+		for ctx.Err() == nil {
+			s, err := a.next(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					yield(Sample{}, err)
+				}
+				return
+			}
+			if !yield(s, nil) {
+				return
+			}
+		}
+	}
+}
+
+// AcquireChan runs [ADS1256.Acquire] in a go routine and delivers the samples on a channel
+// buffered for bufSize samples. When the buffer is full, acquisition waits for the consumer;
+// the ADC lock is not held meanwhile.
+//
+// The channel is closed when ctx is done or acquisition fails. The returned function waits
+// for that and returns the error that ended acquisition, if any.
+func (adc *ADS1256) AcquireChan(ctx context.Context, opts AcquireOptions, bufSize int, pairs ...ChannelPair) (<-chan Sample, func() error) {
+	samples := make(chan Sample, max(bufSize, 0))
+	done := make(chan struct{})
+	var err error
+
+	go func() {
+		defer close(done)
+		defer close(samples)
+		for s, aerr := range adc.Acquire(ctx, opts, pairs...) {
+			if aerr != nil {
+				err = aerr
+				return
+			}
+			select {
+			case samples <- s:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return samples, func() error {
+		<-done
+		return err
+	}
+}
+
+// sleepCtx sleeps for d, or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
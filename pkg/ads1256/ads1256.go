@@ -1,6 +1,7 @@
 package ads1256
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -16,7 +17,8 @@ type SerialInterface interface {
 	Write(data []byte, start bool, stop bool) (uint, error)
 
 	// WaitDRDY is called to wait for DRDY pin == LOW.
-	WaitDRDY() error
+	// It must give up and return ctx.Err() once ctx is done.
+	WaitDRDY(ctx context.Context) error
 
 	// PowerDown pulls the PWDN pin low.
 	PowerDown() error
@@ -44,7 +46,17 @@ type ADS1256 struct {
 	regLR [NumRegisters]byte // "Last Read"  register data
 	regLW [NumRegisters]byte // "Last Write" register data
 
+	cfg    Config // Configuration last passed to Initialize
+	timing Timing // Delays derived from the configured clocks
+
+	seq     uint64          // Sequence number of the last [Sample], across all channel pairs
+	chanSeq map[byte]uint64 // Sequence number of the last [Sample] per channel pair, keyed by MUX value
+
 	continuousMode *atomic.Bool
+	calPending     atomic.Bool // A register write started an auto-calibration (STATUS ACAL)
+
+	drdyTime atomic.Int64 // Total time spent waiting for DRDY, in nanoseconds
+	spiTime  atomic.Int64 // Total time spent on SPI and CS transfers, in nanoseconds
 }
 
 // Config represents user-level configuration parameters
@@ -54,6 +66,37 @@ type Config struct {
 	BufferEn bool // Enable the ADC's internal buffer
 	AutoCal  bool // If set, device auto-calibrates after certain register changes
 	ClkOut   byte // 0=Off, 1=CLK/1, 2=CLK/2, 3=CLK/4
+	LSBFirst bool // Set STATUS ORDER: conversion data bits are sent least significant first
+
+	// SensorDetect selects the sensor-detect current source: ADCON_SDCS_OFF,
+	// ADCON_SDCS_0p5uA, ADCON_SDCS_2uA or ADCON_SDCS_10uA.
+	SensorDetect byte
+
+	// VRef is the reference voltage (VREFP - VREFN) used to convert codes to volts.
+	// 0 means [DefaultVRef].
+	VRef float64
+
+	// DRDYTimeout is how long to wait for a conversion before giving up with [ErrDRDYTimeout].
+	// 0 derives it from the data rate in effect.
+	DRDYTimeout time.Duration
+
+	// ClockHz is the master clock (fCLKIN) of the board. 0 means [DefaultClockHz].
+	// Data rates, settling times and command delays all scale with it.
+	ClockHz float64
+	// SCLKHz is the SPI clock the serial interface runs at. 0 means [DefaultSCLKHz].
+	// It may not exceed fCLKIN/4, and limits the data rate a [Stream] can keep up with.
+	SCLKHz float64
+	// TimingMargin is added to every datasheet delay, as a fraction (0.25 = +25%).
+	TimingMargin float64
+
+	// Calibration, if set, is restored by Initialize instead of running a SELFCAL.
+	Calibration *CalibrationProfile
+	// ProfileMaxAge is how old Calibration may be before it is refused. 0 means [DefaultProfileMaxAge].
+	ProfileMaxAge time.Duration
+
+	// InitAttempts is how often Initialize resets and configures the device before giving up
+	// when the chip ID or a register read back is wrong. 0 means [DefaultInitAttempts].
+	InitAttempts int
 }
 
 // DefaultConfig provides default config. You can adjust as needed
@@ -64,6 +107,9 @@ func DefaultConfig() Config {
 		BufferEn: false,
 		AutoCal:  false,
 		ClkOut:   0, // Turn off CLKOUT
+
+		SensorDetect: ADCON_SDCS_OFF,
+		VRef:         DefaultVRef,
 	}
 }
 
@@ -71,97 +117,128 @@ func DefaultConfig() Config {
 func NewADS1256(spi SerialInterface) *ADS1256 {
 	return &ADS1256{
 		spi:            spi,
+		timing:         DefaultConfig().timing(),
 		continuousMode: new(atomic.Bool),
 	}
 }
 
-func (adc *ADS1256) WaitDRDY() error {
-	return adc.spi.WaitDRDY()
-}
-
-// Initialize sets up the device with the provided config.
-// Call it once at start-up. The ADS1256 automatically does a self-cal on power-up,
-// but you can do an additional SELFCAL if needed.
-func (adc *ADS1256) Initialize(cfg Config) error {
-	adc.mu.Lock()
-
+// configure resets the device, writes the configuration, and verifies the chip ID and every
+// written register by reading them back.
+func (adc *ADS1256) configure(cfg Config) error {
 	// Issue hardware or software Reset if desired:
 	if err := adc.Reset(); err != nil {
-		adc.mu.Unlock()
 		return err
 	}
 
-	// Wait a bit for device to run internal power-up routines
-	time.Sleep(50 * time.Millisecond) // 30ms is typical after hardware Reset
+	// ID bits are read-only
+	status := Status{Order: cfg.LSBFirst, ACal: cfg.AutoCal, BufEn: cfg.BufferEn}
+
+	adcon := ADCON{PGA: cfg.PGA, ClkOut: cfg.ClkOut, SDCS: sdcsField(cfg.SensorDetect)}
 
-	// Build the STATUS register byte
-	var statusVal byte = 0x00
-	if cfg.BufferEn {
-		statusVal |= STATUS_BUFEN
+	// STATUS, MUX, ADCON and DRATE are consecutive, so write them in one burst.
+	// MUX is set to its power-up default of AIN0/AIN1.
+	regs := []byte{
+		status.Byte(),
+		Mux{Pos: CH_AIN0, Neg: CH_AIN1}.Byte(),
+		adcon.Byte(),
+		cfg.DataRate,
 	}
-	if cfg.AutoCal {
-		statusVal |= STATUS_ACAL
+	if err := adc.writeRegisters(REG_STATUS, regs); err != nil {
+		return err
 	}
 
-	// ORDER bit remains 0 => MSB first
-	// ID bits are read-only
+	// The I/O register is left at its default (0xE0: D0 output, D1..D3 inputs);
+	// use SetPinDirection, WritePin and ReadPin to drive D0..D3.
 
-	// Write the STATUS register
-	if err := adc.writeRegister(REG_STATUS, statusVal); err != nil {
-		adc.mu.Unlock()
+	if err := adc.readAllRegisters(); err != nil {
+		return fmt.Errorf("failed to read back registers: %w", err)
+	}
+	if err := adc.checkChipID(); err != nil {
 		return err
 	}
+	return adc.verifyRegisters(REG_STATUS, regs)
+}
 
-	// Build the ADCON register
-	// bit7 always 0, bits6-5 = CLK bits, bits4-3=SensorDetect, bits2-0=PGA
-	var adconVal byte
-	switch cfg.ClkOut {
-	case 1:
-		adconVal = ADCON_CLK_DIV1
-	case 2:
-		adconVal = ADCON_CLK_DIV2
-	case 3:
-		adconVal = ADCON_CLK_DIV4
-	default:
-		adconVal = ADCON_CLK_OFF
-	}
-
-	// no sensor detect current by default
-	// TODO: make this a parameter
-	adconVal |= ADCON_SDCS_OFF
-	// adconVal |= AdconSDCS2uA
-
-	//goland:noinspection GoRedundantParens
-	adconVal |= (cfg.PGA & 0x07) // set PGA bits
-	if err := adc.writeRegister(REG_ADCON, adconVal); err != nil {
-		adc.mu.Unlock()
+// Initialize sets up the device with the provided config.
+// Call it once at start-up. The ADS1256 automatically does a self-cal on power-up,
+// but the new PGA and data rate settings need another, so Initialize finishes with
+// a SELFCAL and waits for it to complete, unless [Config.Calibration] provides a
+// saved profile to restore instead. Invalid settings, and a profile that fails
+// [CalibrationProfile.Check], are rejected before the device is touched.
+//
+// Every register Initialize writes is read back and compared, and the STATUS ID bits are
+// checked, so a wrong SPI mode or a bad cable fails here with a [ChipIDError] or a
+// [RegisterMismatchError] rather than producing garbage data later. Failed verifications are
+// retried [Config.InitAttempts] times.
+func (adc *ADS1256) Initialize(cfg Config) error {
+	if err := cfg.Settings().Validate(); err != nil {
+		return err
+	}
+	if cfg.ClkOut > 3 {
+		return fmt.Errorf("%w: ClkOut %d", ErrInvalidSetting, cfg.ClkOut)
+	}
+	timing := cfg.timing()
+	if err := timing.Validate(); err != nil {
 		return err
 	}
+	if cfg.Calibration != nil {
+		if err := cfg.Calibration.Check(cfg); err != nil {
+			return err
+		}
+	}
+
+	adc.mu.Lock()
+
+	adc.cfg = cfg
+	adc.timing = timing
+
+	attempts := cfg.InitAttempts
+	if attempts <= 0 {
+		attempts = DefaultInitAttempts
+	}
 
-	if err := adc.writeRegister(REG_DRATE, cfg.DataRate); err != nil {
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if err = adc.configure(cfg); !isVerifyError(err) {
+			break
+		}
+	}
+	if err != nil {
 		adc.mu.Unlock()
+		if isVerifyError(err) {
+			return fmt.Errorf("device verification failed after %d attempts: %w", attempts, err)
+		}
 		return err
 	}
 
-	// Optionally set up the I/O register if you want to manipulate D0..D3
-	// By default, all are inputs. Example: 0xE0 is default => (DIR3..DIR0 =1 => inputs)
-	// We'll leave that as default for now.
-
-	// Read them back to confirm (optional)
-	if err := adc.readAllRegisters(); err != nil {
+	if cfg.Calibration == nil {
+		if err := adc.calibrate(CMD_SELFCAL); err != nil {
+			adc.mu.Unlock()
+			return err
+		}
 		adc.mu.Unlock()
-		return fmt.Errorf("failed to read back registers: %v", err)
+		return nil
 	}
 
-	if err := adc.sendCommand(CMD_SELFCAL); err != nil {
+	// With AutoCal set, the register writes above started a calibration that
+	// would overwrite the restored values when it finishes.
+	if err := adc.waitCalibration(); err != nil {
 		adc.mu.Unlock()
 		return err
 	}
 
-	/*	if err := adc.spi.WaitDRDY(); err != nil {
+	for attempt := 0; attempt < attempts; attempt++ {
+		if err = adc.restoreCalibration(*cfg.Calibration); err != nil {
+			break
+		}
+		if err = adc.verifyCalibration(*cfg.Calibration); !isVerifyError(err) {
+			break
+		}
+	}
+	if err != nil {
 		adc.mu.Unlock()
-		return err
-	}*/
+		return fmt.Errorf("failed to restore calibration profile: %w", err)
+	}
 
 	adc.mu.Unlock()
 	return nil
@@ -175,9 +252,23 @@ func (adc *ADS1256) Close() error {
 	return adc.spi.PowerDown()
 }
 
-// Reset triggers a software Reset using the RESET command
+// Reset triggers a software Reset using the RESET command, and waits until the device
+// has restarted and completed its first conversion.
 func (adc *ADS1256) Reset() error {
-	return adc.sendCommand(CMD_RESET)
+	if err := adc.sendCommand(CMD_RESET); err != nil {
+		return err
+	}
+	adc.continuousMode.Store(false) // RESET also ends RDATAC
+	adc.calPending.Store(false)
+	if err := adc.setCSHigh(); err != nil {
+		return err
+	}
+	// RESET restores DRATE to its default
+	err := adc.waitDRDYFor(context.Background(), adc.timing.drdyTimeout(DRATE_DR_30000_SPS))
+	if err != nil {
+		return fmt.Errorf("device did not restart after RESET: %w", err)
+	}
+	return nil
 }
 
 // Standby puts the device into standby mode, shutting down analog but leaving the oscillator running.
@@ -199,44 +290,52 @@ func (adc *ADS1256) Sync() error {
 // PowerDown pulls the PWDN pin low if setPWDN is provided.
 // Holding SYNC/PDWN low for 20 DRDY cycles also powers down the chip.
 func (adc *ADS1256) PowerDown() error {
-	return adc.spi.PowerDown() // drive PWDN pin low
+	return opError(OP_POWER, 0, transportError(adc.spi.PowerDown())) // drive PWDN pin low
 }
 
 // PowerUp pulls the PWDN pin high if setPWDN is provided.
 func (adc *ADS1256) PowerUp() error {
-	return adc.spi.PowerUp() // drive PWDN pin high
+	return opError(OP_POWER, 0, transportError(adc.spi.PowerUp())) // drive PWDN pin high
 }
 
 // SingleConversion issues a Sync, [Wakeup], then RDATA flow to read one sample.
 // Often used in "one-shot" mode. The user typically calls Standby() first,
 // then SingleConversion() each time they want a measurement.
 func (adc *ADS1256) SingleConversion() (int32, error) {
+	return adc.SingleConversionContext(context.Background())
+}
+
+// SingleConversionContext is [ADS1256.SingleConversion], giving up when ctx is done or
+// DRDY times out (see [Config.DRDYTimeout]).
+func (adc *ADS1256) SingleConversionContext(ctx context.Context) (int32, error) {
 	adc.mu.Lock()
+	n, _, err := adc.singleConversion(ctx)
+	adc.mu.Unlock()
+	return n, err
+}
 
+// singleConversion is [ADS1256.SingleConversionContext] without locking. It also returns the time
+// at which the conversion completed.
+func (adc *ADS1256) singleConversion(ctx context.Context) (int32, time.Time, error) {
 	// SYNC
 	if err := adc.Sync(); err != nil {
-		adc.mu.Unlock()
-		return 0, err
+		return 0, time.Time{}, err
 	}
 
 	// WAKEUP
 	if err := adc.Wakeup(); err != nil {
-		adc.mu.Unlock()
-		return 0, err
+		return 0, time.Time{}, err
 	}
 
 	// Wait for DRDY
-	if err := adc.spi.WaitDRDY(); err != nil {
-		adc.mu.Unlock()
-		return 0, err
+	if err := adc.waitDRDY(ctx); err != nil {
+		return 0, time.Time{}, err
 	}
+	ready := time.Now()
 
 	// Then read data with RDATA
 	n, err := adc.readDataByCommand()
-
-	adc.mu.Unlock()
-
-	return n, err
+	return n, ready, err
 }
 
 // RData performs RDATA to get a single 24-bit result from the device.
@@ -248,68 +347,95 @@ func (adc *ADS1256) RData() (int32, error) {
 }
 
 // readDataByCommand performs the RDATA command to get a single 24-bit result from the device.
-func (adc *ADS1256) readDataByCommand() (int32, error) {
+func (adc *ADS1256) readDataByCommand() (_ int32, err error) {
+	defer func() { err = opError(OP_READ_DATA, CMD_RDATA, err) }()
 	if err := adc.setCSLow(); err != nil {
 		return 0, err
 	}
 
-	_, err := adc.Write([]byte{CMD_RDATA})
-	if err != nil {
+	if _, err := adc.Write([]byte{CMD_RDATA}); err != nil {
 		return 0, errors.Join(err, adc.setCSHigh())
 	}
 
-	time.Sleep(200 * time.Microsecond)
+	delay(adc.timing.T6())
 
 	buf := get3Bytes()
-	_, err = adc.Read(buf)
-	if err != nil {
+	if _, err := adc.Read(buf); err != nil {
 		put3Bytes(buf)
 		return 0, errors.Join(err, adc.setCSHigh())
 	}
 
-	raw := Convert24To32(buf)
+	raw := adc.decode(buf)
 
 	put3Bytes(buf)
+	delay(adc.timing.T11(CMD_RDATA))
 	return raw, adc.setCSHigh()
 }
 
 // ReadChannel configures the multiplexer to read from (ainP, ainN),
-// then issues a [CMD_SYNC]->[CMD_WAKEUP] sequence, and finally reads the 24-bit raw value.
+// then issues a [CMD_SYNC]->[CMD_WAKEUP] sequence, waits for the new conversion,
+// and finally reads the 24-bit raw value.
 //
 // Example usage:
 //
 //	code, err := adc.ReadChannel(CH_AIN0, CH_AINCOM)
 func (adc *ADS1256) ReadChannel(ainP, ainN Channel) (int32, error) {
+	return adc.ReadChannelContext(context.Background(), ainP, ainN)
+}
+
+// ReadChannelContext is [ADS1256.ReadChannel], giving up when ctx is done or DRDY times out
+// (see [Config.DRDYTimeout]).
+func (adc *ADS1256) ReadChannelContext(ctx context.Context, ainP, ainN Channel) (int32, error) {
 	adc.mu.Lock()
+	val, err := adc.readChannel(ctx, ainP, ainN)
+	adc.mu.Unlock()
+	return val, err
+}
 
-	if err := adc.WaitDRDY(); err != nil {
-		adc.mu.Unlock()
-		return 0, err
-	}
+// readChannel is [ADS1256.ReadChannelContext] without locking.
+func (adc *ADS1256) readChannel(ctx context.Context, ainP, ainN Channel) (int32, error) {
+	code, _, err := adc.readPairAt(ctx, ChannelPair{Pos: ainP, Neg: ainN})
+	return code, err
+}
 
-	// Write to MUX register: top 4 bits => ainP, bottom 4 => ainN
-	muxVal := byte((ainP << 4) | (ainN & 0x0F))
+// readPairAt converts pair, applying its settings if it carries any, and returns the result
+// together with the time at which the conversion completed.
+func (adc *ADS1256) readPairAt(ctx context.Context, pair ChannelPair) (int32, time.Time, error) {
+	if err := adc.waitDRDY(ctx); err != nil {
+		return 0, time.Time{}, err
+	}
 
-	if err := adc.writeRegister(REG_MUX, muxVal); err != nil {
-		adc.mu.Unlock()
-		return 0, fmt.Errorf("failed to set MUX: %v", err)
+	if err := adc.startConversion(pair); err != nil {
+		return 0, time.Time{}, err
 	}
 
-	if err := adc.Sync(); err != nil {
-		adc.mu.Unlock()
-		return 0, fmt.Errorf("failed SYNC cmd: %v", err)
+	// The data register still holds the previous input's result until the
+	// conversion started by WAKEUP completes.
+	if err := adc.waitDRDY(ctx); err != nil {
+		return 0, time.Time{}, err
 	}
+	ready := time.Now()
 
-	time.Sleep(5 * time.Microsecond)
+	code, err := adc.readDataByCommand()
+	return code, ready, err
+}
 
-	if err := adc.Wakeup(); err != nil {
-		adc.mu.Unlock()
-		return 0, fmt.Errorf("failed WAKEUP cmd: %v", err)
+// startConversion selects pair in the multiplexer and restarts the conversion with SYNC and WAKEUP.
+// If the pair carries acquisition settings, they are applied first.
+func (adc *ADS1256) startConversion(pair ChannelPair) error {
+	if pair.Settings != nil {
+		if err := adc.applySettings(*pair.Settings); err != nil {
+			return err
+		}
 	}
 
-	time.Sleep(1 * time.Microsecond)
+	if err := adc.writeRegister(REG_MUX, Mux{Pos: pair.Pos, Neg: pair.Neg}.Byte()); err != nil {
+		return err
+	}
 
-	adc.mu.Unlock()
-	val, err := adc.readDataByCommand()
-	return val, err
+	if err := adc.Sync(); err != nil {
+		return err
+	}
+
+	return adc.Wakeup()
 }
@@ -0,0 +1,148 @@
+package ads1256
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCalibrationTimeout is returned when DRDY does not go low within the time
+// a calibration is expected to take at the current data rate.
+var ErrCalibrationTimeout = classError(ErrDevice, "calibration did not complete in time")
+
+// calibrationTimeout returns how long to wait for DRDY after a calibration command.
+// The slowest calibration in the datasheet (SELFCAL) takes a little over three
+// conversion periods; we allow eight plus some slack for USB latency.
+func (t Timing) calibrationTimeout(drate byte) time.Duration {
+	return 8*t.dataPeriod(drate) + 50*time.Millisecond
+}
+
+// SelfCalibrate performs a self offset and self gain calibration (SELFCAL) and
+// blocks until it completes.
+func (adc *ADS1256) SelfCalibrate() error {
+	adc.mu.Lock()
+	err := adc.calibrate(CMD_SELFCAL)
+	adc.mu.Unlock()
+	return err
+}
+
+// SelfOffsetCalibrate performs a self offset calibration (SELFOCAL) and blocks until it completes.
+func (adc *ADS1256) SelfOffsetCalibrate() error {
+	adc.mu.Lock()
+	err := adc.calibrate(CMD_SELFOCAL)
+	adc.mu.Unlock()
+	return err
+}
+
+// SelfGainCalibrate performs a self gain calibration (SELFGCAL) and blocks until it completes.
+func (adc *ADS1256) SelfGainCalibrate() error {
+	adc.mu.Lock()
+	err := adc.calibrate(CMD_SELFGCAL)
+	adc.mu.Unlock()
+	return err
+}
+
+// SystemOffsetCalibrate performs a system offset calibration (SYSOCAL) and blocks until it completes.
+// The selected inputs must be shorted together (zero differential input) while it runs.
+func (adc *ADS1256) SystemOffsetCalibrate() error {
+	adc.mu.Lock()
+	err := adc.calibrate(CMD_SYSOCAL)
+	adc.mu.Unlock()
+	return err
+}
+
+// SystemGainCalibrate performs a system gain calibration (SYSGCAL) and blocks until it completes.
+// A positive full-scale voltage must be applied to the selected inputs while it runs.
+func (adc *ADS1256) SystemGainCalibrate() error {
+	adc.mu.Lock()
+	err := adc.calibrate(CMD_SYSGCAL)
+	adc.mu.Unlock()
+	return err
+}
+
+// calibrate issues one of the calibration commands and waits for DRDY to signal completion.
+func (adc *ADS1256) calibrate(cmd byte) error {
+	if err := adc.sendCommand(cmd); err != nil {
+		return err
+	}
+	if err := adc.setCSHigh(); err != nil {
+		return err
+	}
+
+	if err := adc.waitCalibration(); err != nil {
+		return opError(OP_COMMAND, cmd, err)
+	}
+	return nil
+}
+
+// waitCalibration waits for DRDY to signal the end of a calibration.
+func (adc *ADS1256) waitCalibration() error {
+	err := adc.waitDRDYFor(context.Background(), adc.timing.calibrationTimeout(adc.regLW[REG_DRATE]))
+	if errors.Is(err, ErrDRDYTimeout) {
+		return fmt.Errorf("%w: %w", ErrCalibrationTimeout, err)
+	}
+	return err
+}
+
+// OffsetCalibration returns the 24-bit signed offset calibration value held in OFC0..OFC2.
+func (adc *ADS1256) OffsetCalibration() (int32, error) {
+	adc.mu.Lock()
+	ofc, err := adc.readOffsetCalibration()
+	adc.mu.Unlock()
+	return ofc, err
+}
+
+// SetOffsetCalibration writes a 24-bit signed offset calibration value to OFC0..OFC2.
+func (adc *ADS1256) SetOffsetCalibration(ofc int32) error {
+	adc.mu.Lock()
+	err := adc.writeOffsetCalibration(ofc)
+	adc.mu.Unlock()
+	return err
+}
+
+// FullScaleCalibration returns the 24-bit unsigned full-scale calibration value held in FSC0..FSC2.
+func (adc *ADS1256) FullScaleCalibration() (uint32, error) {
+	adc.mu.Lock()
+	fsc, err := adc.readFullScaleCalibration()
+	adc.mu.Unlock()
+	return fsc, err
+}
+
+// SetFullScaleCalibration writes a 24-bit unsigned full-scale calibration value to FSC0..FSC2.
+func (adc *ADS1256) SetFullScaleCalibration(fsc uint32) error {
+	adc.mu.Lock()
+	err := adc.writeFullScaleCalibration(fsc)
+	adc.mu.Unlock()
+	return err
+}
+
+func (adc *ADS1256) readOffsetCalibration() (int32, error) {
+	buf, err := adc.readRegisters(REG_OFC0, 3)
+	if err != nil {
+		return 0, err
+	}
+	return decodeOFC(buf[0], buf[1], buf[2]), nil
+}
+
+func (adc *ADS1256) writeOffsetCalibration(ofc int32) error {
+	if ofc < -(1<<23) || ofc > 1<<23-1 {
+		return fmt.Errorf("%w: offset calibration %d out of 24-bit range", ErrInvalidSetting, ofc)
+	}
+	return adc.writeRegisters(REG_OFC0, []byte{byte(ofc), byte(ofc >> 8), byte(ofc >> 16)})
+}
+
+func (adc *ADS1256) readFullScaleCalibration() (uint32, error) {
+	buf, err := adc.readRegisters(REG_FSC0, 3)
+	if err != nil {
+		return 0, err
+	}
+	return decodeFSC(buf[0], buf[1], buf[2]), nil
+}
+
+func (adc *ADS1256) writeFullScaleCalibration(fsc uint32) error {
+	if fsc > 0xFFFFFF {
+		return fmt.Errorf("%w: full-scale calibration 0x%X out of 24-bit range", ErrInvalidSetting, fsc)
+	}
+	return adc.writeRegisters(REG_FSC0, []byte{byte(fsc), byte(fsc >> 8), byte(fsc >> 16)})
+}
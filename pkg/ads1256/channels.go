@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"sync"
-	"sync/atomic"
 	"time"
 )
 
@@ -58,25 +57,95 @@ func (c Channel) String() string {
 type ChannelPair struct {
 	Pos Channel
 	Neg Channel
+
+	// Settings optionally overrides the PGA, data rate, buffer and sensor-detect settings
+	// from [Config] for this pair when it is scanned. Pairs passed to a scan callback always
+	// carry the settings they were acquired with; treat them as read-only.
+	Settings *AcquisitionSettings
 }
 
+// ScanMode selects how a [ChannelScan] moves between channel pairs.
+type ScanMode int
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	// SCAN_RDATAC exits continuous mode, writes MUX and re-enters RDATAC for every pair.
+	SCAN_RDATAC ScanMode = iota
+	// SCAN_CYCLING uses the datasheet's fast channel cycling sequence: after DRDY, the next
+	// pair's MUX is written and SYNC/WAKEUP start its conversion, then RDATA reads the result
+	// of the pair that just finished. The MUX change is pipelined with the readout.
+	SCAN_CYCLING
+)
+
+func (m ScanMode) String() string {
+	switch m {
+	case SCAN_RDATAC:
+		return "rdatac"
+	case SCAN_CYCLING:
+		return "cycling"
+	default:
+		return "(invalid scan mode)"
+	}
+}
+
+// ScanOptions configures a channel scan started with [ADS1256.StartScan].
+type ScanOptions struct {
+	Interval time.Duration  // Pause between passes over the channel pairs
+	Mode     ScanMode       // How to switch between pairs
+	OnData   DataCallback   // Called with every result, while the ADC lock is held
+	OnSample SampleCallback // Called with every result as a [Sample], after OnData
+
+	// Period, if set, starts passes on a fixed timeline instead of Interval after the previous
+	// pass: pass n starts at the start of the scan plus (n-1)*Period, so [Sample.Cycle] tells
+	// when a sample was due. A pass that overruns the next start time is a deadline miss,
+	// handled according to Overrun and reported to OnMiss.
+	Period  time.Duration
+	Overrun OverrunPolicy
+	OnMiss  MissCallback
+}
+
+// ErrScanStopped is returned by [ChannelScan.Pause] and [ChannelScan.Resume] once the scan has stopped.
+var ErrScanStopped = classError(ErrConfig, "channel scan stopped")
+
+// maxScanErrors is the number of errors after which a scan gives up.
+const maxScanErrors = 50
+
+// ChannelScan is a running scan started with [ADS1256.StartScan].
 type ChannelScan struct {
 	Interval time.Duration
-	done     *atomic.Bool
-	running  *atomic.Bool
+	mode     ScanMode
+	primed   bool   // SCAN_CYCLING: a conversion of pairs[0] has been started
+	cycle    uint64 // Index of the current pass
+	period   time.Duration
+	overrun  OverrunPolicy
+	onMiss   MissCallback
 	pairs    []ChannelPair
 	callback DataCallback
-	err      []error
-	errMu    sync.Mutex
+	onSample SampleCallback
+
+	adc    *ADS1256
+	stats  *scanCounters
+	cancel context.CancelFunc
+	done   chan struct{} // Closed once the scan goroutine has exited
+
+	stateMu sync.Mutex
+	paused  bool          // The device was put in STANDBY by Pause
+	resumed chan struct{} // Closed by Resume
+	stopped bool          // The scan goroutine is exiting; Pause and Resume no longer apply
+
+	err   []error
+	errMu sync.Mutex
 }
 
-func NewChannelScan(interval time.Duration, pairs []ChannelPair, onData DataCallback) *ChannelScan {
+// newChannelScan returns the state of a scan that [ADS1256.StartScan] is about to start.
+func newChannelScan(interval time.Duration, pairs []ChannelPair, onData DataCallback) *ChannelScan {
 	return &ChannelScan{
 		Interval: interval,
-		done:     &atomic.Bool{},
-		running:  &atomic.Bool{},
 		pairs:    pairs,
 		callback: onData,
+		stats:    newScanCounters(pairs),
+		cancel:   func() {},
+		done:     make(chan struct{}),
 		err:      make([]error, 0),
 	}
 }
@@ -85,76 +154,216 @@ func (cs *ChannelScan) addErr(err error) {
 	if err == nil {
 		return
 	}
+	cs.stats.addErr(err)
 	cs.errMu.Lock()
 	cs.err = append(cs.err, err)
-	if len(cs.err) > 50 {
-		cs.done.Store(true)
+	if len(cs.err) > maxScanErrors {
+		cs.cancel()
 	}
 	cs.errMu.Unlock()
 }
 
+// Err returns the errors the scan has run into so far, joined, or nil if there were none.
+// Once [ChannelScan.Done] is closed it is the final result of the scan.
 func (cs *ChannelScan) Err() error {
 	cs.errMu.Lock()
+	defer cs.errMu.Unlock()
 	if len(cs.err) == 0 {
 		return nil
 	}
-	err := errors.Join(cs.err...)
-	cs.errMu.Unlock()
-	return fmt.Errorf("channel scan errors: %w", err)
+	return fmt.Errorf("channel scan errors: %w", errors.Join(cs.err...))
 }
 
+// Stop asks the scan to stop after the channel pair being converted. It does not wait;
+// use [ChannelScan.Done] or [ChannelScan.Wait] for that.
 func (cs *ChannelScan) Stop() {
-	cs.done.Store(true)
+	cs.cancel()
+}
+
+// Done is closed once the scan has stopped, the device has left RDATAC mode and, if the scan
+// was paused, has been woken up again.
+func (cs *ChannelScan) Done() <-chan struct{} {
+	return cs.done
 }
 
+// IsDone reports whether the scan has stopped.
 func (cs *ChannelScan) IsDone() bool {
-	return cs.done.Load()
+	select {
+	case <-cs.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait blocks until the scan has stopped and returns its final error (see [ChannelScan.Err]).
+// If ctx is done first it returns ctx.Err(); the scan keeps running.
+func (cs *ChannelScan) Wait(ctx context.Context) error {
+	select {
+	case <-cs.done:
+		return cs.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-func (cs *ChannelScan) Wait(ctx ...context.Context) error {
-	ctxDone := func() {
-		if len(ctx) < 1 {
+// Pause puts the device in STANDBY once the channel pair being converted is done, and parks the
+// scan until [ChannelScan.Resume]. Pausing a paused scan does nothing.
+func (cs *ChannelScan) Pause() error {
+	cs.adc.mu.Lock()
+	defer cs.adc.mu.Unlock()
+	cs.stateMu.Lock()
+	defer cs.stateMu.Unlock()
+
+	switch {
+	case cs.stopped:
+		return ErrScanStopped
+	case cs.paused:
+		return nil
+	}
+	if err := cs.adc.sendCommand(CMD_STANDBY); err != nil {
+		return err
+	}
+	cs.paused = true
+	cs.resumed = make(chan struct{})
+	cs.primed = false // STANDBY ends the conversion started by the last pass
+	return nil
+}
+
+// Resume wakes the device from STANDBY and continues a paused scan with the next pass.
+// Resuming a scan that is not paused does nothing.
+func (cs *ChannelScan) Resume() error {
+	cs.adc.mu.Lock()
+	defer cs.adc.mu.Unlock()
+	cs.stateMu.Lock()
+	defer cs.stateMu.Unlock()
+
+	switch {
+	case cs.stopped:
+		return ErrScanStopped
+	case !cs.paused:
+		return nil
+	}
+	if err := cs.adc.Wakeup(); err != nil {
+		return err
+	}
+	cs.paused = false
+	close(cs.resumed)
+	cs.stats.resume()
+	return nil
+}
+
+// Paused reports whether the scan is paused.
+func (cs *ChannelScan) Paused() bool {
+	cs.stateMu.Lock()
+	defer cs.stateMu.Unlock()
+	return cs.paused
+}
+
+// parked returns a channel that is closed on Resume if the scan is paused, or nil if it is not.
+func (cs *ChannelScan) parked() <-chan struct{} {
+	cs.stateMu.Lock()
+	defer cs.stateMu.Unlock()
+	if !cs.paused {
+		return nil
+	}
+	return cs.resumed
+}
+
+// run performs passes until ctx is done, then leaves the device idle and closes cs.done.
+func (cs *ChannelScan) run(ctx context.Context, pass func(ctx context.Context, cs *ChannelScan, cancel context.CancelFunc)) {
+	defer close(cs.done)
+	defer cs.finish()
+
+	cs.stats.start(cs.adc)
+	sched := schedule{start: time.Now(), period: cs.period, policy: cs.overrun}
+	next := uint64(1)
+
+	for ctx.Err() == nil {
+		if resumed := cs.parked(); resumed != nil {
+			select {
+			case <-resumed:
+			case <-ctx.Done():
+			}
+			if cs.period > 0 {
+				next = sched.resync(next, time.Now())
+			}
+			continue
+		}
+
+		if cs.period > 0 && sleepUntil(ctx, sched.due(next)) != nil {
 			return
 		}
-		select {
-		case <-ctx[0].Done():
-		default:
+		cs.cycle = next
+		pass(ctx, cs, cs.cancel)
+		interrupted := ctx.Err() != nil || cs.Paused()
+		cs.stats.endPass(cs.cycle, interrupted)
+
+		if cs.period <= 0 {
+			next++
+			_ = sleepCtx(ctx, cs.Interval)
+			continue
+		}
+
+		var miss *DeadlineMiss
+		if next, miss = sched.next(cs.cycle, time.Now()); miss != nil && !interrupted {
+			cs.stats.miss(*miss)
+			if cs.onMiss != nil {
+				cs.onMiss(*miss)
+			}
 		}
 	}
+}
 
-	for !cs.done.Load() {
-		ctxDone()
-		time.Sleep(10 * time.Millisecond)
+// finish takes the device out of RDATAC mode or STANDBY, whichever the scan left it in.
+func (cs *ChannelScan) finish() {
+	adc := cs.adc
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+	cs.stateMu.Lock()
+	defer cs.stateMu.Unlock()
+
+	cs.stopped = true
+	if cs.paused {
+		cs.addErr(adc.Wakeup())
+		cs.paused = false
+		close(cs.resumed)
 	}
-	for cs.running.Load() {
-		ctxDone()
-		time.Sleep(10 * time.Millisecond)
+	if adc.continuousMode.Load() {
+		cs.addErr(adc.stopContinuous())
 	}
-
-	return cs.Err()
 }
 
 type DataCallback func(chPair ChannelPair, code int32)
 
-func (adc *ADS1256) scanChannelPairs(cs *ChannelScan, cancel context.CancelFunc) {
-	if cs.done.Load() {
-		cancel()
-		return
+// deliver passes a result of the current pass to the scan's callbacks. ready is the time DRDY
+// was observed for the conversion. The caller must hold adc.mu.
+func (adc *ADS1256) deliver(cs *ChannelScan, chPair ChannelPair, code int32, ready time.Time) {
+	sample := adc.newSample(chPair, code, ready, cs.cycle)
+	cs.stats.sample(adc, chPair, ready, cs.cycle)
+	if cs.callback != nil {
+		cs.callback(chPair, code)
 	}
+	if cs.onSample != nil {
+		cs.onSample(sample)
+	}
+}
 
+func (adc *ADS1256) scanChannelPairs(ctx context.Context, cs *ChannelScan, cancel context.CancelFunc) {
 	for _, chPair := range cs.pairs {
-		println("scanning", chPair.Pos, chPair.Neg)
-
-		if cs.done.Load() {
-			cancel()
+		if ctx.Err() != nil {
 			return
 		}
 
 		adc.mu.Lock()
 
-		if adc.continuousMode.Load() {
-			println("exiting continuous mode")
+		// Pause may have taken the lock between two pairs
+		if cs.Paused() {
+			adc.mu.Unlock()
+			return
+		}
 
+		if adc.continuousMode.Load() {
 			// exit existing continuous mode if active
 			if err := adc.sendCommand(CMD_SDATAC); err != nil {
 				cancel()
@@ -165,10 +374,15 @@ func (adc *ADS1256) scanChannelPairs(cs *ChannelScan, cancel context.CancelFunc)
 			adc.continuousMode.Store(false)
 		}
 
-		// set multiplexer to read from the current channel pair
-		muxVal := byte((chPair.Pos << 4) | (chPair.Neg & 0x0F))
+		if err := adc.applySettings(*chPair.Settings); err != nil {
+			cancel()
+			cs.addErr(err)
+			adc.mu.Unlock()
+			return
+		}
 
-		fmt.Printf("writing to REG_MUX: %08b\n", muxVal)
+		// set multiplexer to read from the current channel pair
+		muxVal := Mux{Pos: chPair.Pos, Neg: chPair.Neg}.Byte()
 
 		if err := adc.writeRegister(REG_MUX, muxVal); err != nil {
 			cancel()
@@ -182,8 +396,6 @@ func (adc *ADS1256) scanChannelPairs(cs *ChannelScan, cancel context.CancelFunc)
 		// _ = adc.sendCommand(CMD_WAKEUP)
 		// might want to wait DRDY or a small delay.
 
-		println("sending RDATAC")
-
 		// start continuous read
 		if err := adc.sendCommand(CMD_RDATAC); err != nil {
 			cancel()
@@ -194,42 +406,38 @@ func (adc *ADS1256) scanChannelPairs(cs *ChannelScan, cancel context.CancelFunc)
 
 		adc.continuousMode.Store(true)
 
-		println("waiting for DRDY")
-		cs.addErr(adc.WaitDRDY())
+		if err := adc.waitDRDY(ctx); err != nil {
+			if ctx.Err() != nil {
+				adc.mu.Unlock()
+				return
+			}
+			cs.addErr(err)
+		}
+		ready := time.Now()
 
 		// read 3 bytes
 		// In RDATAC, after DRDY you simply clock out 3 bytes
 		rawBuf := get3Bytes()
 
-		println("setting CS low")
-
 		cs.addErr(adc.setCSLow())
 
-		println("reading 3 bytes")
-
 		n, err := adc.Read(rawBuf)
 		if err != nil && !errors.Is(err, io.EOF) {
-			cs.addErr(err)
+			cs.addErr(opError(OP_READ_DATA, CMD_RDATAC, err))
 		}
 
 		if n != 3 {
-			cs.addErr(fmt.Errorf("%w: expected 3 bytes, got %d", io.ErrUnexpectedEOF, n))
+			cs.addErr(opError(OP_READ_DATA, CMD_RDATAC, transportError(fmt.Errorf("%w: expected 3 bytes, got %d", io.ErrUnexpectedEOF, n))))
 		}
 
-		fmt.Printf("rawBuf: %08b\n", rawBuf)
-
-		println("setting CS high")
-
 		cs.addErr(adc.setCSHigh())
 
 		// convert to int32
-		code := Convert24To32(rawBuf)
+		code := adc.decode(rawBuf)
 
 		put3Bytes(rawBuf)
 
-		fmt.Printf("code: %d\nrunning call back\n", code)
-
-		cs.callback(chPair, code)
+		adc.deliver(cs, chPair, code, ready)
 
 		adc.mu.Unlock()
 	}
@@ -250,40 +458,52 @@ func (adc *ADS1256) ScanChannelsContinuously(
 	onData DataCallback,
 	pairs ...ChannelPair,
 ) (*ChannelScan, error) {
+	return adc.StartScan(ctx, ScanOptions{Interval: scanInterval, Mode: SCAN_RDATAC, OnData: onData}, pairs...)
+}
+
+// StartScan spawns a go routine that repeatedly scans the given channel pairs
+// as configured by opts, until ctx is cancelled or the scan is stopped.
+//
+// Each pair is acquired with its own [ChannelPair.Settings], or with the settings from
+// [Config] if it has none. STATUS, ADCON and DRATE are only rewritten when the next
+// pair needs a different value.
+func (adc *ADS1256) StartScan(ctx context.Context, opts ScanOptions, pairs ...ChannelPair) (*ChannelScan, error) {
 	// Quick check that we have at least one channel pair.
 	if len(pairs) == 0 {
-		return nil, errors.New("no channels to scan")
+		return nil, fmt.Errorf("%w to scan", ErrNoChannels)
 	}
 
-	if adc.continuousMode.Load() {
-		if err := adc.sendCommand(CMD_SDATAC); err != nil {
-			return nil, fmt.Errorf("failed to send SDATAC (continuous mode was already enabled): %w", err)
-		}
-		adc.continuousMode.Store(false)
+	var pass func(ctx context.Context, cs *ChannelScan, cancel context.CancelFunc)
+	switch opts.Mode {
+	case SCAN_RDATAC:
+		pass = adc.scanChannelPairs
+	case SCAN_CYCLING:
+		pass = adc.cycleChannelPairs
+	default:
+		return nil, fmt.Errorf("%w: scan mode %d", ErrInvalidSetting, opts.Mode)
+	}
+	if opts.Period < 0 {
+		return nil, fmt.Errorf("%w: scan period %s", ErrInvalidSetting, opts.Period)
+	}
+	if opts.Overrun != OVERRUN_SKIP && opts.Overrun != OVERRUN_CATCH_UP {
+		return nil, fmt.Errorf("%w: overrun policy %d", ErrInvalidSetting, opts.Overrun)
 	}
 
-	var cancel context.CancelFunc
-	ctx, cancel = context.WithCancel(ctx)
+	pairs, err := adc.resolvePairs(pairs)
+	if err != nil {
+		return nil, err
+	}
 
-	chScan := NewChannelScan(scanInterval, pairs, onData)
+	chScan := newChannelScan(opts.Interval, pairs, opts.OnData)
+	chScan.mode = opts.Mode
+	chScan.onSample = opts.OnSample
+	chScan.period = opts.Period
+	chScan.overrun = opts.Overrun
+	chScan.onMiss = opts.OnMiss
+	chScan.adc = adc
+	ctx, chScan.cancel = context.WithCancel(ctx)
 
-	go func() {
-		chScan.running.Store(true)
-		for {
-			select {
-			case <-ctx.Done():
-				chScan.running.Store(false)
-				return
-			default:
-				if chScan.done.Load() {
-					cancel()
-					continue
-				}
-			}
-			adc.scanChannelPairs(chScan, cancel)
-			time.Sleep(scanInterval)
-		}
-	}()
+	go chScan.run(ctx, pass)
 
 	return chScan, nil
 }
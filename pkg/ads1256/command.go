@@ -1,11 +1,9 @@
 package ads1256
 
-import (
-	"errors"
-	"time"
-)
+import "errors"
 
-func (adc *ADS1256) sendCommand(cmd byte) error {
+func (adc *ADS1256) sendCommand(cmd byte) (err error) {
+	defer func() { err = opError(OP_COMMAND, cmd, err) }()
 	if err := adc.setCSLow(); err != nil {
 		return err
 	}
@@ -16,15 +14,16 @@ func (adc *ADS1256) sendCommand(cmd byte) error {
 			return errors.Join(err, adc.setCSHigh())
 		}
 		adc.continuousMode.Store(false)
-		time.Sleep(100 * time.Microsecond)
+		delay(adc.timing.T11(CMD_SDATAC))
 	}
 
 	// Write the command
-	_, err := adc.Write([]byte{cmd})
-	if err != nil {
+	if _, err := adc.Write([]byte{cmd}); err != nil {
 		return errors.Join(err, adc.setCSHigh())
 	}
 
+	delay(adc.timing.T11(cmd))
+
 	// If we just sent RDATAC
 	if cmd == CMD_RDATAC {
 		adc.continuousMode.Store(true)
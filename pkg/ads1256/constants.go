@@ -55,26 +55,27 @@ const (
 )
 
 // DRATE Register Byte constants (for DRATE register):
-// fCLKIN assumed = 7.68 MHz. Data Rate is from Table in data sheet.
+// The names give the data rate at the nominal fCLKIN of 7.68 MHz, from the table in the data sheet.
+// The actual rate scales with the master clock; see [DataRateAt] and [Config.ClockHz].
 //
 //goland:noinspection GoSnakeCaseUsage,GoUnusedConst
 const (
-	DRATE_DR_2p5_SPS   = 0x00
-	DRATE_DR_5_SPS     = 0x01
-	DRATE_DR_10_SPS    = 0x02
-	DRATE_DR_15_SPS    = 0x03
-	DRATE_DR_25_SPS    = 0x04
-	DRATE_DR_30_SPS    = 0x05
-	DRATE_DR_50_SPS    = 0x06
-	DRATE_DR_60_SPS    = 0x07
-	DRATE_DR_100_SPS   = 0x08
-	DRATE_DR_500_SPS   = 0x09
-	DRATE_DR_1000_SPS  = 0x0A
-	DRATE_DR_2000_SPS  = 0x0B
-	DRATE_DR_3750_SPS  = 0x0C
-	DRATE_DR_7500_SPS  = 0x0D
-	DRATE_DR_15000_SPS = 0x0E
-	DRATE_DR_30000_SPS = 0x0F
+	DRATE_DR_2p5_SPS   = 0x03
+	DRATE_DR_5_SPS     = 0x13
+	DRATE_DR_10_SPS    = 0x23
+	DRATE_DR_15_SPS    = 0x33
+	DRATE_DR_25_SPS    = 0x43
+	DRATE_DR_30_SPS    = 0x53
+	DRATE_DR_50_SPS    = 0x63
+	DRATE_DR_60_SPS    = 0x72
+	DRATE_DR_100_SPS   = 0x82
+	DRATE_DR_500_SPS   = 0x92
+	DRATE_DR_1000_SPS  = 0xA1
+	DRATE_DR_2000_SPS  = 0xB0
+	DRATE_DR_3750_SPS  = 0xC0
+	DRATE_DR_7500_SPS  = 0xD0
+	DRATE_DR_15000_SPS = 0xE0
+	DRATE_DR_30000_SPS = 0xF0
 )
 
 // Bits for the STATUS register
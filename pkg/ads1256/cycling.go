@@ -0,0 +1,78 @@
+package ads1256
+
+import (
+	"context"
+	"time"
+)
+
+// cycleChannelPairs performs one pass over cs.pairs using the fast channel cycling
+// sequence from the datasheet:
+//
+//	WaitDRDY -> WREG MUX (next pair) -> SYNC -> WAKEUP -> RDATA (previous pair)
+//
+// The conversion of the next pair runs while the previous result is read out, so every
+// RDATA returns the pair that was selected before the MUX change. The sequence wraps
+// around to the first pair, so consecutive passes stay pipelined.
+//
+// The ADC lock is held for the whole pass so that nothing can change MUX between steps.
+// Per-pair settings are applied together with the MUX change; the output register keeps
+// the previous result, so it is still read back correctly.
+func (adc *ADS1256) cycleChannelPairs(ctx context.Context, cs *ChannelScan, cancel context.CancelFunc) {
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+
+	// Pause may have taken the lock before this pass
+	if ctx.Err() != nil || cs.Paused() {
+		return
+	}
+
+	fail := func(err error) {
+		cancel()
+		cs.addErr(err)
+	}
+
+	// (Re)start the pipeline unless the first pair's conversion was started by the
+	// previous pass and nobody has touched the MUX since.
+	first := Mux{Pos: cs.pairs[0].Pos, Neg: cs.pairs[0].Neg}.Byte()
+	if !cs.primed || adc.continuousMode.Load() || adc.regLW[REG_MUX] != first || adc.settingsChanged(*cs.pairs[0].Settings) {
+		if err := adc.startConversion(cs.pairs[0]); err != nil {
+			fail(err)
+			return
+		}
+		cs.primed = true
+	}
+
+	for i, chPair := range cs.pairs {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// conversion of chPair is complete
+		if err := adc.waitDRDY(ctx); err != nil {
+			cs.primed = false
+			if ctx.Err() != nil {
+				return
+			}
+			fail(err)
+			return
+		}
+		ready := time.Now()
+
+		// start converting the next pair while chPair's result is still in the output register
+		next := cs.pairs[(i+1)%len(cs.pairs)]
+		if err := adc.startConversion(next); err != nil {
+			cs.primed = false
+			fail(err)
+			return
+		}
+
+		code, err := adc.readDataByCommand()
+		if err != nil {
+			cs.primed = false
+			fail(err)
+			return
+		}
+
+		adc.deliver(cs, chPair, code, ready)
+	}
+}
@@ -0,0 +1,128 @@
+package ads1256
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// dataRates maps DRATE register codes to output data rates in samples per second,
+// for fCLKIN = 7.68 MHz. Source: Table 18 of the datasheet.
+var dataRates = map[byte]float64{
+	DRATE_DR_2p5_SPS:   2.5,
+	DRATE_DR_5_SPS:     5,
+	DRATE_DR_10_SPS:    10,
+	DRATE_DR_15_SPS:    15,
+	DRATE_DR_25_SPS:    25,
+	DRATE_DR_30_SPS:    30,
+	DRATE_DR_50_SPS:    50,
+	DRATE_DR_60_SPS:    60,
+	DRATE_DR_100_SPS:   100,
+	DRATE_DR_500_SPS:   500,
+	DRATE_DR_1000_SPS:  1000,
+	DRATE_DR_2000_SPS:  2000,
+	DRATE_DR_3750_SPS:  3750,
+	DRATE_DR_7500_SPS:  7500,
+	DRATE_DR_15000_SPS: 15000,
+	DRATE_DR_30000_SPS: 30000,
+}
+
+// DataRateInfo describes what a DRATE code means at a particular master clock. The digital
+// filter, and with it every figure here, scales linearly with fCLKIN.
+type DataRateInfo struct {
+	Code     byte
+	ClockHz  float64       // fCLKIN the figures apply to
+	SPS      float64       // Output data rate
+	Notch    float64       // First notch of the digital filter, in Hz; it repeats at every multiple
+	Settling time.Duration // Time from WAKEUP after SYNC or a MUX change to the first settled result
+}
+
+// Period returns the time between conversions in continuous conversion.
+func (d DataRateInfo) Period() time.Duration {
+	return time.Duration(float64(time.Second) / d.SPS)
+}
+
+// Rejects reports whether the filter has a notch at f, e.g. at 50 or 60 Hz line frequency.
+func (d DataRateInfo) Rejects(f float64) bool {
+	n := f / d.Notch
+	return n >= 1 && math.Abs(n-math.Round(n)) < 1e-9
+}
+
+func (d DataRateInfo) String() string {
+	return fmt.Sprintf("DRATE{0x%02X: %g SPS at %g MHz, settling %s}", d.Code, d.SPS, d.ClockHz/1e6, d.Settling)
+}
+
+// DataRateAt returns the actual data rate, filter notch and settling time of a DRATE code
+// for a master clock of clockHz. A clockHz of 0 means [DefaultClockHz].
+func DataRateAt(code byte, clockHz float64) (DataRateInfo, error) {
+	if clockHz == 0 {
+		clockHz = DefaultClockHz
+	}
+	if clockHz < 0 {
+		return DataRateInfo{}, fmt.Errorf("%w: fCLKIN %g Hz", ErrInvalidSetting, clockHz)
+	}
+	nominal, ok := dataRates[code]
+	if !ok {
+		return DataRateInfo{}, fmt.Errorf("%w: data rate 0x%02X", ErrInvalidSetting, code)
+	}
+	sps := nominal * clockHz / DefaultClockHz
+	return DataRateInfo{
+		Code:     code,
+		ClockHz:  clockHz,
+		SPS:      sps,
+		Notch:    sps,
+		Settling: Timing{ClockHz: clockHz}.Settling(code),
+	}, nil
+}
+
+// DataRateTable returns every DRATE setting at a master clock of clockHz, slowest first.
+// A clockHz of 0 means [DefaultClockHz].
+func DataRateTable(clockHz float64) ([]DataRateInfo, error) {
+	table := make([]DataRateInfo, 0, len(dataRates))
+	for code := range dataRates {
+		info, err := DataRateAt(code, clockHz)
+		if err != nil {
+			return nil, err
+		}
+		table = append(table, info)
+	}
+	sort.Slice(table, func(i, j int) bool { return table[i].SPS < table[j].SPS })
+	return table, nil
+}
+
+// DataRateFor returns the DRATE setting whose actual data rate at a master clock of clockHz is
+// closest to sps. A clockHz of 0 means [DefaultClockHz].
+func DataRateFor(sps, clockHz float64) (DataRateInfo, error) {
+	if sps <= 0 {
+		return DataRateInfo{}, fmt.Errorf("%w: data rate %g SPS", ErrInvalidSetting, sps)
+	}
+	table, err := DataRateTable(clockHz)
+	if err != nil {
+		return DataRateInfo{}, err
+	}
+	best := table[0]
+	for _, info := range table[1:] {
+		// compare on a log scale, the rates span four decades
+		if math.Abs(math.Log(info.SPS/sps)) < math.Abs(math.Log(best.SPS/sps)) {
+			best = info
+		}
+	}
+	return best, nil
+}
+
+// DataRateInfo returns the actual data rate, filter notch and settling time of a DRATE code
+// at the configured master clock (see [Config.ClockHz]).
+func (adc *ADS1256) DataRateInfo(code byte) (DataRateInfo, error) {
+	return DataRateAt(code, adc.Timing().ClockHz)
+}
+
+// dataPeriod returns the time between conversions for a DRATE code at the master clock.
+// Unknown codes are treated as the slowest rate so that derived timeouts stay conservative.
+func (t Timing) dataPeriod(drate byte) time.Duration {
+	info, err := DataRateAt(drate, t.ClockHz)
+	if err != nil {
+		info, _ = DataRateAt(DRATE_DR_2p5_SPS, t.ClockHz)
+	}
+	return info.Period()
+}
@@ -0,0 +1,66 @@
+package ads1256
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDRDYTimeout is returned when DRDY does not go low within the expected time, e.g. because
+// the pin is miswired or the chip is powered down or in standby.
+var ErrDRDYTimeout = classError(ErrDevice, "timed out waiting for DRDY")
+
+// drdyTimeout returns how long to wait for one conversion at a data rate: twice the settling
+// time after SYNC or a MUX change, plus slack for USB latency.
+func (t Timing) drdyTimeout(drate byte) time.Duration {
+	return 2*t.Settling(drate) + 50*time.Millisecond
+}
+
+// drdyTimeout returns the DRDY deadline for the current data rate, or [Config.DRDYTimeout] if set.
+// If a register write has just started an auto-calibration, the deadline allows for it.
+func (adc *ADS1256) drdyTimeout() time.Duration {
+	if adc.cfg.DRDYTimeout > 0 {
+		return adc.cfg.DRDYTimeout
+	}
+	drate := adc.regLW[REG_DRATE]
+	if adc.calPending.Load() {
+		return max(adc.timing.calibrationTimeout(drate), adc.timing.drdyTimeout(drate))
+	}
+	return adc.timing.drdyTimeout(drate)
+}
+
+// WaitDRDY waits for DRDY to go low. It returns ctx.Err() if ctx is done first, and
+// [ErrDRDYTimeout] if DRDY has not gone low within the deadline for the current data rate
+// (see [Config.DRDYTimeout]).
+func (adc *ADS1256) WaitDRDY(ctx context.Context) error {
+	adc.mu.RLock()
+	timeout := adc.drdyTimeout()
+	adc.mu.RUnlock()
+	return adc.waitDRDYFor(ctx, timeout)
+}
+
+// waitDRDY is [ADS1256.WaitDRDY] for callers holding adc.mu.
+func (adc *ADS1256) waitDRDY(ctx context.Context) error {
+	return adc.waitDRDYFor(ctx, adc.drdyTimeout())
+}
+
+// waitDRDYFor waits for DRDY to go low, giving up with [ErrDRDYTimeout] after timeout.
+func (adc *ADS1256) waitDRDYFor(ctx context.Context, timeout time.Duration) error {
+	defer adc.timeDRDY(time.Now())
+	wctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := adc.spi.WaitDRDY(wctx)
+	if err == nil {
+		adc.calPending.Store(false)
+	}
+	switch {
+	case err == nil || ctx.Err() != nil:
+		return err
+	case errors.Is(err, context.DeadlineExceeded):
+		return opError(OP_WAIT_DRDY, 0, fmt.Errorf("%w after %s", ErrDRDYTimeout, timeout))
+	default:
+		return opError(OP_WAIT_DRDY, 0, transportError(err))
+	}
+}
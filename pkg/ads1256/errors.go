@@ -0,0 +1,169 @@
+package ads1256
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error classes. Every error returned by this package, other than a plain context error,
+// matches one of them with [errors.Is], so callers can decide how to react:
+//
+//   - [ErrTransport]: the [SerialInterface] failed (USB, SPI or GPIO). Usually transient;
+//     retry, or reopen the interface. The interface's own error stays in the chain.
+//   - [ErrConfig]: an argument, [Config] value or calibration profile is invalid or cannot be
+//     read, or a call is not valid in the current state, e.g. pausing a stopped scan.
+//     Retrying will not help.
+//   - [ErrDevice]: the chip did not behave as expected, e.g. a wrong chip ID, a register
+//     read-back mismatch or a DRDY timeout. Reset or power cycle it.
+var (
+	ErrTransport = errors.New("transport error")
+	ErrConfig    = errors.New("invalid configuration")
+	ErrDevice    = errors.New("device fault")
+)
+
+var (
+	// ErrInvalidRegister is returned for a register address or count outside the register map.
+	ErrInvalidRegister = classError(ErrConfig, "invalid register")
+	// ErrInvalidPin is returned for a [DigitalPin] other than D0..D3.
+	ErrInvalidPin = classError(ErrConfig, "no such pin")
+	// ErrInvalidSetting is returned for a PGA, data rate, clock or other setting the device
+	// or this package does not accept.
+	ErrInvalidSetting = classError(ErrConfig, "invalid setting")
+	// ErrNoChannels is returned when a scan or acquisition is started without channel pairs.
+	ErrNoChannels = classError(ErrConfig, "no channels")
+)
+
+// classedError is a sentinel error that also matches its class.
+type classedError struct {
+	msg   string
+	class error
+}
+
+func classError(class error, msg string) error {
+	return &classedError{msg: msg, class: class}
+}
+
+func (e *classedError) Error() string {
+	return e.msg
+}
+
+func (e *classedError) Is(target error) bool {
+	return target == e.class
+}
+
+// transportError marks an error returned by the [SerialInterface] as matching [ErrTransport].
+func transportError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrTransport, err)
+}
+
+// configError marks an error from outside the device, e.g. a file system error, as matching
+// [ErrConfig], unless it already matches a class.
+func configError(err error) error {
+	if err == nil || errors.Is(err, ErrConfig) || errors.Is(err, ErrTransport) || errors.Is(err, ErrDevice) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrConfig, err)
+}
+
+// Op is the operation an [OpError] occurred in.
+type Op string
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	OP_COMMAND        Op = "command"
+	OP_READ_REGISTER  Op = "read register"
+	OP_WRITE_REGISTER Op = "write register"
+	OP_READ_DATA      Op = "read data"
+	OP_WAIT_DRDY      Op = "wait DRDY"
+	OP_POWER          Op = "power"
+)
+
+// OpError records the device operation an error occurred in. Err holds the cause, which
+// matches one of the error classes.
+type OpError struct {
+	Op       Op
+	Opcode   byte     // Command byte sent, if any; includes the register for RREG and WREG
+	Register Register // First register, for OP_READ_REGISTER and OP_WRITE_REGISTER
+	Err      error
+}
+
+func (e *OpError) Error() string {
+	switch e.Op {
+	case OP_READ_REGISTER, OP_WRITE_REGISTER:
+		return fmt.Sprintf("%s %s: %v", e.Op, e.Register, e.Err)
+	case OP_COMMAND, OP_READ_DATA:
+		return fmt.Sprintf("%s %s (0x%02X): %v", e.Op, commandName(e.Opcode), e.Opcode, e.Err)
+	default:
+		return fmt.Sprintf("%s: %v", e.Op, e.Err)
+	}
+}
+
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// opError wraps a non-nil err in an [OpError].
+func opError(op Op, opcode byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &OpError{Op: op, Opcode: opcode, Err: err}
+}
+
+// registerError wraps a non-nil err from a register transaction in an [OpError].
+func registerError(op Op, start byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	opcode := CMD_RREG
+	if op == OP_WRITE_REGISTER {
+		opcode = CMD_WREG
+	}
+	return &OpError{Op: op, Opcode: byte(opcode) | start&0x0F, Register: Register(start), Err: err}
+}
+
+var commandNames = map[byte]string{
+	CMD_WAKEUP0:  "WAKEUP",
+	CMD_RDATA:    "RDATA",
+	CMD_RDATAC:   "RDATAC",
+	CMD_SDATAC:   "SDATAC",
+	CMD_SELFCAL:  "SELFCAL",
+	CMD_SELFOCAL: "SELFOCAL",
+	CMD_SELFGCAL: "SELFGCAL",
+	CMD_SYSOCAL:  "SYSOCAL",
+	CMD_SYSGCAL:  "SYSGCAL",
+	CMD_SYNC:     "SYNC",
+	CMD_STANDBY:  "STANDBY",
+	CMD_RESET:    "RESET",
+	CMD_WAKEUP:   "WAKEUP",
+}
+
+func commandName(opcode byte) string {
+	switch opcode & 0xF0 {
+	case CMD_RREG:
+		return "RREG"
+	case CMD_WREG:
+		return "WREG"
+	}
+	if name, ok := commandNames[opcode]; ok {
+		return name
+	}
+	return "(unknown command)"
+}
+
+// PinError is returned when a [DigitalPin] cannot be used.
+type PinError struct {
+	Pin DigitalPin
+	Err error
+}
+
+func (e *PinError) Error() string {
+	return fmt.Sprintf("digital pin %d: %v", int(e.Pin), e.Err)
+}
+
+func (e *PinError) Unwrap() error {
+	return e.Err
+}
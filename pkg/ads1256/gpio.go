@@ -0,0 +1,128 @@
+package ads1256
+
+import "fmt"
+
+// DigitalPin identifies one of the ADS1256 digital I/O pins, controlled through REG_IO.
+type DigitalPin int
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	PIN_D0 DigitalPin = iota // Shared with CLKOUT
+	PIN_D1
+	PIN_D2
+	PIN_D3
+)
+
+func (p DigitalPin) String() string {
+	if p < PIN_D0 || p > PIN_D3 {
+		return "(invalid pin)"
+	}
+	return fmt.Sprintf("D%d", int(p))
+}
+
+// PinDirection is the direction of a [DigitalPin].
+type PinDirection int
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	DIR_OUTPUT PinDirection = iota
+	DIR_INPUT
+)
+
+func (d PinDirection) String() string {
+	if d == DIR_INPUT {
+		return "input"
+	}
+	return "output"
+}
+
+// ErrPinConflict is returned when D0 is used as a GPIO while [Config.ClkOut] routes CLKOUT to it.
+var ErrPinConflict = classError(ErrConfig, "D0 is in use as CLKOUT")
+
+func (adc *ADS1256) checkPin(pin DigitalPin) error {
+	if pin < PIN_D0 || pin > PIN_D3 {
+		return &PinError{Pin: pin, Err: ErrInvalidPin}
+	}
+	if pin == PIN_D0 && adc.cfg.ClkOut != 0 {
+		return &PinError{Pin: pin, Err: fmt.Errorf("%w: ClkOut=%d", ErrPinConflict, adc.cfg.ClkOut)}
+	}
+	return nil
+}
+
+// updateIO performs a read-modify-write of REG_IO.
+func (adc *ADS1256) updateIO(modify func(io *IO)) error {
+	b, err := adc.readRegister(REG_IO)
+	if err != nil {
+		return err
+	}
+	io := ParseIO(b)
+	modify(&io)
+	return adc.writeRegister(REG_IO, io.Byte())
+}
+
+// SetPinDirection configures a digital pin as an input or an output.
+func (adc *ADS1256) SetPinDirection(pin DigitalPin, dir PinDirection) error {
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+	if err := adc.checkPin(pin); err != nil {
+		return err
+	}
+	return adc.updateIO(func(io *IO) {
+		io.Input[pin] = dir == DIR_INPUT
+	})
+}
+
+// PinDirection reads the current direction of a digital pin from the device.
+func (adc *ADS1256) PinDirection(pin DigitalPin) (PinDirection, error) {
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+	if err := adc.checkPin(pin); err != nil {
+		return DIR_INPUT, err
+	}
+	b, err := adc.readRegister(REG_IO)
+	if err != nil {
+		return DIR_INPUT, err
+	}
+	if ParseIO(b).Input[pin] {
+		return DIR_INPUT, nil
+	}
+	return DIR_OUTPUT, nil
+}
+
+// WritePin drives an output pin high or low. The pin must already be configured as an output.
+func (adc *ADS1256) WritePin(pin DigitalPin, high bool) error {
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+	if err := adc.checkPin(pin); err != nil {
+		return err
+	}
+	return adc.updateIO(func(io *IO) {
+		io.Level[pin] = high
+	})
+}
+
+// ReadPin returns the level of a digital pin. For inputs this is the level driven onto the pin,
+// for outputs it is the level last written.
+func (adc *ADS1256) ReadPin(pin DigitalPin) (bool, error) {
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+	if err := adc.checkPin(pin); err != nil {
+		return false, err
+	}
+	b, err := adc.readRegister(REG_IO)
+	if err != nil {
+		return false, err
+	}
+	return ParseIO(b).Level[pin], nil
+}
+
+// ReadPins reads the direction and level of all four digital pins at once.
+func (adc *ADS1256) ReadPins() (IO, error) {
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+	b, err := adc.readRegister(REG_IO)
+	if err != nil {
+		return IO{}, err
+	}
+	return ParseIO(b), nil
+}
@@ -0,0 +1,229 @@
+package ads1256
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// peakToPeakFactor relates peak-to-peak to RMS noise, as used by the datasheet for noise-free
+// resolution: 99.9% of Gaussian noise lies within ±3.3σ.
+const peakToPeakFactor = 6.6
+
+// NoiseRow holds the input-referred RMS noise, in µV, at one data rate for every PGA setting.
+type NoiseRow struct {
+	SPS   float64    `json:"sps"`      // Actual data rate; the DRATE_DR_* names at fCLKIN = 7.68 MHz
+	Noise [7]float64 `json:"noise_uv"` // Indexed by ADCON_PGA_1 to ADCON_PGA_64
+}
+
+// NoiseTable is a reference for [ADS1256.CharacterizeNoise] to compare measured noise with,
+// such as the noise tables of the datasheet for the buffer setting and VREF in use.
+type NoiseTable []NoiseRow
+
+// Lookup returns the RMS noise in µV for a PGA setting at an actual data rate, as given by
+// [DataRateAt]. Noise depends on the rate rather than the DRATE code, so a table for the
+// nominal 7.68 MHz clock has no figures for the rates of another clock.
+func (t NoiseTable) Lookup(pga byte, sps float64) (float64, bool) {
+	if pga > ADCON_PGA_64 {
+		return 0, false
+	}
+	for _, row := range t {
+		// allow for rounding in the rate derived from the clock
+		if math.Abs(row.SPS-sps) <= sps*1e-6 && row.Noise[pga] > 0 {
+			return row.Noise[pga], true
+		}
+	}
+	return 0, false
+}
+
+// NoiseOptions configures [ADS1256.CharacterizeNoise].
+type NoiseOptions struct {
+	Samples   int        // Conversions per combination, at least 2
+	PGAs      []byte     // ADCON_PGA_* settings to measure at; empty means the configured PGA
+	DataRates []byte     // DRATE_DR_* settings to measure at; empty means the configured data rate
+	Reference NoiseTable // Figures to compare with; nil means no comparison
+
+	// OnResult, if set, is called with each result as soon as it is complete.
+	OnResult func(r NoiseResult)
+}
+
+// NoiseResult is the noise measured on one channel pair at one PGA and data rate setting.
+//
+// Effective resolution is log2(FSR / RMS noise) and noise-free resolution is
+// log2(FSR / peak-to-peak noise), with a full-scale range (FSR) of 2^24 codes. If no noise was
+// seen at all, NoNoise is set and both are left out: the resolution is beyond what the
+// conversions can show.
+type NoiseResult struct {
+	Pair     string  `json:"pair"` // e.g. "CH_AIN0/CH_AINCOM"
+	PGA      byte    `json:"pga"`
+	Gain     int     `json:"gain"`
+	DataRate byte    `json:"drate"`
+	SPS      float64 `json:"sps"` // Actual data rate at the configured clock
+	Samples  int     `json:"samples"`
+	Clipped  int     `json:"clipped"` // Out of range conversions; if any, the figures are meaningless
+
+	MeanVolts            float64 `json:"mean_v"`
+	RMSCodes             float64 `json:"rms_codes"`
+	PeakToPeakCodes      float64 `json:"pp_codes"`
+	RMSMicrovolts        float64 `json:"rms_uv"`
+	PeakToPeakMicrovolts float64 `json:"pp_uv"`
+	EffectiveBits        float64 `json:"enob,omitempty"`
+	NoiseFreeBits        float64 `json:"noise_free_bits,omitempty"`
+	NoNoise              bool    `json:"no_noise,omitempty"` // Every conversion returned the same code
+
+	// Reference is the reference figure for the same settings, if the table has one.
+	Reference *NoiseReference `json:"reference,omitempty"`
+}
+
+// NoiseReference is the noise a [NoiseTable] gives for the settings of a [NoiseResult], with
+// the resolution that follows from it at the reference voltage of the measurement. As in the
+// datasheet, peak-to-peak noise is taken as 6.6 times the RMS noise.
+type NoiseReference struct {
+	RMSMicrovolts float64 `json:"rms_uv"`
+	EffectiveBits float64 `json:"enob"`
+	NoiseFreeBits float64 `json:"noise_free_bits"`
+	Ratio         float64 `json:"ratio"` // Measured over reference RMS noise
+}
+
+func (r NoiseResult) String() string {
+	s := fmt.Sprintf("%s PGA %d, %g SPS: %.3fµV RMS (%.2f codes), %.3fµV p-p, %.2f ENOB, %.2f noise-free bits",
+		r.Pair, r.Gain, r.SPS, r.RMSMicrovolts, r.RMSCodes, r.PeakToPeakMicrovolts, r.EffectiveBits, r.NoiseFreeBits)
+	if r.NoNoise {
+		s = fmt.Sprintf("%s PGA %d, %g SPS: no noise in %d samples", r.Pair, r.Gain, r.SPS, r.Samples)
+	}
+	if r.Reference != nil {
+		s += fmt.Sprintf(" (%.2f× reference %.3fµV RMS, %.2f ENOB)", r.Reference.Ratio, r.Reference.RMSMicrovolts, r.Reference.EffectiveBits)
+	}
+	if r.Clipped > 0 {
+		s += fmt.Sprintf(", %d clipped", r.Clipped)
+	}
+	return s
+}
+
+// CharacterizeNoise measures the noise of each channel pair at every combination of the PGA
+// and data rate settings in opts, taking opts.Samples conversions per combination. To measure
+// the noise of the ADC itself, short the inputs of the pairs, e.g. to AINCOM.
+//
+// Results are returned in order of pair, then PGA, then data rate. The other acquisition
+// settings come from the pair's [ChannelPair.Settings], or from [Config]; like a scan, the
+// measurement leaves the settings of the last combination in the registers. If acquisition
+// fails or ctx is done, the results so far are returned with the error.
+func (adc *ADS1256) CharacterizeNoise(ctx context.Context, opts NoiseOptions, pairs ...ChannelPair) ([]NoiseResult, error) {
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("%w to characterize", ErrNoChannels)
+	}
+	if opts.Samples < 2 {
+		return nil, fmt.Errorf("%w: %d samples per noise measurement", ErrInvalidSetting, opts.Samples)
+	}
+	adc.mu.RLock()
+	defaults := adc.cfg.Settings()
+	vRef := adc.vRef()
+	clockHz := adc.timing.ClockHz
+	adc.mu.RUnlock()
+
+	pgas, rates := opts.PGAs, opts.DataRates
+	if len(pgas) == 0 {
+		pgas = []byte{defaults.PGA}
+	}
+	if len(rates) == 0 {
+		rates = []byte{defaults.DataRate}
+	}
+
+	var results []NoiseResult
+	for _, pair := range pairs {
+		settings := defaults
+		if pair.Settings != nil {
+			settings = *pair.Settings
+		}
+		for _, pga := range pgas {
+			for _, dataRate := range rates {
+				s := settings
+				s.PGA, s.DataRate = pga, dataRate
+				pair.Settings = &s
+
+				r, err := adc.measureNoise(ctx, pair, opts, vRef, clockHz)
+				if err != nil {
+					return results, err
+				}
+				results = append(results, r)
+				if opts.OnResult != nil {
+					opts.OnResult(r)
+				}
+			}
+		}
+	}
+	return results, nil
+}
+
+// measureNoise takes opts.Samples conversions of pair, with the settings it carries.
+func (adc *ADS1256) measureNoise(ctx context.Context, pair ChannelPair, opts NoiseOptions, vRef, clockHz float64) (NoiseResult, error) {
+	pga, dataRate := pair.Settings.PGA, pair.Settings.DataRate
+	info, err := DataRateAt(dataRate, clockHz)
+	if err != nil {
+		return NoiseResult{}, err
+	}
+	r := NoiseResult{
+		Pair:     fmt.Sprintf("%s/%s", pair.Pos, pair.Neg),
+		PGA:      pga,
+		Gain:     ADCON{PGA: pga}.Gain(),
+		DataRate: dataRate,
+		SPS:      info.SPS,
+	}
+
+	codes := make([]float64, 0, opts.Samples)
+	for s, err := range adc.Acquire(ctx, AcquireOptions{Mode: SCAN_RDATAC}, pair) {
+		if err != nil {
+			return r, err
+		}
+		codes = append(codes, float64(s.Code))
+		if !s.Value.InRange() {
+			r.Clipped++
+		}
+		if len(codes) == opts.Samples {
+			break
+		}
+	}
+	if len(codes) < opts.Samples {
+		return r, ctx.Err()
+	}
+
+	mean, sumSq := 0.0, 0.0
+	lo, hi := codes[0], codes[0]
+	for _, c := range codes {
+		mean += c
+		lo, hi = min(lo, c), max(hi, c)
+	}
+	mean /= float64(len(codes))
+	for _, c := range codes {
+		sumSq += (c - mean) * (c - mean)
+	}
+
+	lsb := LSBVolts(vRef, pga)
+	r.Samples = len(codes)
+	r.MeanVolts = mean * lsb
+	r.RMSCodes = math.Sqrt(sumSq / float64(len(codes)-1))
+	r.PeakToPeakCodes = hi - lo
+	r.RMSMicrovolts = r.RMSCodes * lsb * 1e6
+	r.PeakToPeakMicrovolts = r.PeakToPeakCodes * lsb * 1e6
+	if r.NoNoise = r.PeakToPeakCodes == 0; !r.NoNoise {
+		r.EffectiveBits = resolutionBits(1<<24, r.RMSCodes)
+		r.NoiseFreeBits = resolutionBits(1<<24, r.PeakToPeakCodes)
+	}
+
+	if ref, ok := opts.Reference.Lookup(pga, r.SPS); ok {
+		fsr := 4 * vRef / float64(r.Gain) * 1e6 // in µV
+		r.Reference = &NoiseReference{
+			RMSMicrovolts: ref,
+			EffectiveBits: resolutionBits(fsr, ref),
+			NoiseFreeBits: resolutionBits(fsr, ref*peakToPeakFactor),
+			Ratio:         r.RMSMicrovolts / ref,
+		}
+	}
+	return r, nil
+}
+
+// resolutionBits returns log2(fsr/noise). Reference figures can exceed 24 bits; noise must not
+// be zero.
+func resolutionBits(fsr, noise float64) float64 {
+	return math.Log2(fsr / noise)
+}
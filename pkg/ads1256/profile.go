@@ -0,0 +1,172 @@
+package ads1256
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultProfileMaxAge is how old a [CalibrationProfile] may be before it is considered stale,
+// when [Config.ProfileMaxAge] is not set.
+const DefaultProfileMaxAge = 7 * 24 * time.Hour
+
+var (
+	// ErrProfileMismatch is returned when a [CalibrationProfile] was taken under different
+	// PGA, data rate or buffer settings than the [Config] it is being applied to.
+	ErrProfileMismatch = classError(ErrConfig, "calibration profile does not match configuration")
+
+	// ErrProfileStale is returned when a [CalibrationProfile] is older than the allowed maximum age,
+	// or its contents could not have come from a completed calibration.
+	ErrProfileStale = classError(ErrConfig, "calibration profile is stale")
+)
+
+// CalibrationProfile is a snapshot of the OFC/FSC calibration registers together with the
+// settings they were taken under, so that a calibration can be restored after a power cycle.
+// Profiles are keyed by the serial number of the FT232H the ADS1256 is attached to.
+type CalibrationProfile struct {
+	Serial    string    `json:"serial"`
+	Offset    int32     `json:"ofc"`
+	FullScale uint32    `json:"fsc"`
+	PGA       byte      `json:"pga"`
+	DataRate  byte      `json:"drate"`
+	BufferEn  bool      `json:"buffer_en"`
+	Taken     time.Time `json:"taken"`
+}
+
+// Check verifies that the profile can be applied to a device configured with cfg.
+// It returns [ErrProfileMismatch] or [ErrProfileStale] when it cannot.
+func (p CalibrationProfile) Check(cfg Config) error {
+	if p.PGA != cfg.PGA&0x07 || p.DataRate != cfg.DataRate || p.BufferEn != cfg.BufferEn {
+		return fmt.Errorf(
+			"%w: profile PGA=0x%02X DRATE=0x%02X BUFEN=%t, config PGA=0x%02X DRATE=0x%02X BUFEN=%t",
+			ErrProfileMismatch, p.PGA, p.DataRate, p.BufferEn, cfg.PGA&0x07, cfg.DataRate, cfg.BufferEn,
+		)
+	}
+
+	maxAge := cfg.ProfileMaxAge
+	if maxAge == 0 {
+		maxAge = DefaultProfileMaxAge
+	}
+	age := time.Since(p.Taken)
+	switch {
+	case p.Taken.IsZero() || age < 0:
+		return fmt.Errorf("%w: invalid timestamp %s", ErrProfileStale, p.Taken)
+	case age > maxAge:
+		return fmt.Errorf("%w: taken %s ago, max age is %s", ErrProfileStale, age.Round(time.Second), maxAge)
+	case p.FullScale == 0 || p.FullScale > 0xFFFFFF:
+		return fmt.Errorf("%w: invalid full-scale calibration 0x%X", ErrProfileStale, p.FullScale)
+	case p.Offset < -(1<<23) || p.Offset > 1<<23-1:
+		return fmt.Errorf("%w: invalid offset calibration %d", ErrProfileStale, p.Offset)
+	}
+
+	return nil
+}
+
+// CalibrationProfile captures the current calibration registers and the settings last written
+// to the device into a profile for the given FT232H serial number.
+func (adc *ADS1256) CalibrationProfile(serial string) (CalibrationProfile, error) {
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+
+	cal, err := adc.readRegisters(REG_OFC0, 6)
+	if err != nil {
+		return CalibrationProfile{}, err
+	}
+	ofc := decodeOFC(cal[0], cal[1], cal[2])
+	fsc := decodeFSC(cal[3], cal[4], cal[5])
+
+	return CalibrationProfile{
+		Serial:    serial,
+		Offset:    ofc,
+		FullScale: fsc,
+		PGA:       ParseADCON(adc.regLW[REG_ADCON]).PGA,
+		DataRate:  adc.regLW[REG_DRATE],
+		BufferEn:  ParseStatus(adc.regLW[REG_STATUS]).BufEn,
+		Taken:     time.Now(),
+	}, nil
+}
+
+// restoreCalibration writes a profile's OFC/FSC values back to the device in one burst.
+func (adc *ADS1256) restoreCalibration(p CalibrationProfile) error {
+	ofc, fsc := uint32(p.Offset), p.FullScale
+	return adc.writeRegisters(REG_OFC0, []byte{
+		byte(ofc), byte(ofc >> 8), byte(ofc >> 16),
+		byte(fsc), byte(fsc >> 8), byte(fsc >> 16),
+	})
+}
+
+// verifyCalibration reads back OFC0..FSC2 and compares them with p.
+func (adc *ADS1256) verifyCalibration(p CalibrationProfile) error {
+	if _, err := adc.readRegisters(REG_OFC0, 6); err != nil {
+		return err
+	}
+	ofc, fsc := uint32(p.Offset), p.FullScale
+	return adc.verifyRegisters(REG_OFC0, []byte{
+		byte(ofc), byte(ofc >> 8), byte(ofc >> 16),
+		byte(fsc), byte(fsc >> 8), byte(fsc >> 16),
+	})
+}
+
+func profilePath(dir, serial string) (string, error) {
+	if serial == "" || strings.ContainsAny(serial, `/\`) || serial == "." || serial == ".." {
+		return "", fmt.Errorf("%w: serial %q for calibration profile", ErrInvalidSetting, serial)
+	}
+	return filepath.Join(dir, serial+".json"), nil
+}
+
+// SaveCalibrationProfile writes p to <dir>/<serial>.json, replacing any previous profile for
+// the same serial number. File system errors are wrapped to match [ErrConfig].
+func SaveCalibrationProfile(dir string, p CalibrationProfile) (err error) {
+	defer func() { err = configError(err) }()
+
+	path, err := profilePath(dir, p.Serial)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(p, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+p.Serial+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(append(data, '\n')); err != nil {
+		return errors.Join(err, tmp.Close(), os.Remove(tmp.Name()))
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadCalibrationProfile reads the profile for serial from dir and checks it against cfg.
+// A missing profile is reported with an error matching both [ErrConfig] and [os.ErrNotExist].
+func LoadCalibrationProfile(dir, serial string, cfg Config) (p CalibrationProfile, err error) {
+	defer func() { err = configError(err) }()
+
+	path, err := profilePath(dir, serial)
+	if err != nil {
+		return p, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return p, err
+	}
+	if err = json.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("failed to parse calibration profile %s: %w", path, err)
+	}
+	if p.Serial != serial {
+		return p, fmt.Errorf("%w: profile %s is for serial %q", ErrProfileMismatch, path, p.Serial)
+	}
+
+	return p, p.Check(cfg)
+}
@@ -3,7 +3,6 @@ package ads1256
 import (
 	"errors"
 	"fmt"
-	"time"
 )
 
 func (adc *ADS1256) LastReadRegister(reg Register) byte {
@@ -23,85 +22,151 @@ func (adc *ADS1256) Registers() map[Register]byte {
 	return r
 }
 
+// RegisterSet returns a decoded copy of the last read register values.
+func (adc *ADS1256) RegisterSet() RegisterSet {
+	adc.mu.RLock()
+	rs := ParseRegisterSet(adc.regLR)
+	adc.mu.RUnlock()
+	return rs
+}
+
+// ReadRegisterSet reads every register from the device and returns them decoded.
+func (adc *ADS1256) ReadRegisterSet() (RegisterSet, error) {
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+	if err := adc.readAllRegisters(); err != nil {
+		return RegisterSet{}, err
+	}
+	return ParseRegisterSet(adc.regLR), nil
+}
+
+// WriteRegisters writes values to consecutive registers starting at start, in a single WREG transaction.
+func (adc *ADS1256) WriteRegisters(start Register, values ...byte) error {
+	adc.mu.Lock()
+	err := adc.writeRegisters(byte(start), values)
+	adc.mu.Unlock()
+	return err
+}
+
+// ReadRegisters reads count consecutive registers starting at start, in a single RREG transaction.
+func (adc *ADS1256) ReadRegisters(start Register, count int) ([]byte, error) {
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+	vals, err := adc.readRegisters(byte(start), count)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), vals...), nil
+}
+
+func checkRegisterRange(start byte, count int) error {
+	if start >= NumRegisters {
+		return fmt.Errorf("%w address 0x%02X", ErrInvalidRegister, start)
+	}
+	if count < 1 || int(start)+count > NumRegisters {
+		return fmt.Errorf("%w count %d starting at 0x%02X", ErrInvalidRegister, count, start)
+	}
+	return nil
+}
+
+// exitContinuous sends SDATAC if the device is in continuous read mode. CS must already be low.
+func (adc *ADS1256) exitContinuous() error {
+	if !adc.continuousMode.Load() {
+		return nil
+	}
+	if _, err := adc.Write([]byte{CMD_SDATAC}); err != nil {
+		return err
+	}
+	adc.continuousMode.Store(false)
+	delay(adc.timing.T11(CMD_SDATAC))
+	return nil
+}
+
 // writeRegister writes a single register [regAddr], with the given value.
 func (adc *ADS1256) writeRegister(regAddr, value byte) error {
-	if regAddr >= NumRegisters {
-		return fmt.Errorf("invalid register address 0x%02X", regAddr)
+	return adc.writeRegisters(regAddr, []byte{value})
+}
+
+// writeRegisters writes values to consecutive registers starting at [start] with one WREG command.
+func (adc *ADS1256) writeRegisters(start byte, values []byte) (err error) {
+	defer func() { err = registerError(OP_WRITE_REGISTER, start, err) }()
+	if err := checkRegisterRange(start, len(values)); err != nil {
+		return err
 	}
 	if err := adc.setCSLow(); err != nil {
 		return err
 	}
 
 	// If in continuous read mode, must send SDATAC first
-	if adc.continuousMode.Load() {
-		if _, err := adc.Write([]byte{CMD_SDATAC}); err != nil {
-			return errors.Join(err, adc.setCSHigh())
-		}
-		adc.continuousMode.Store(false)
-
-		time.Sleep(100 * time.Microsecond)
+	if err := adc.exitContinuous(); err != nil {
+		return errors.Join(err, adc.setCSHigh())
 	}
 
-	// WREG: 0x50 + regAddr
-	cmd := CMD_WREG | (regAddr & 0x0F)
-
-	// second byte: # of registers -1. We only do one register => 0
-	out := []byte{cmd, 0x00, value}
+	// WREG: 0x50 + start, then # of registers - 1, then the data
+	out := make([]byte, 0, 2+NumRegisters)
+	out = append(out, CMD_WREG|(start&0x0F), byte(len(values)-1))
+	out = append(out, values...)
 	if _, err := adc.Write(out); err != nil {
 		return errors.Join(err, adc.setCSHigh())
 	}
 
-	// Delay T6 might be needed.
-	time.Sleep(50 * time.Microsecond)
+	delay(adc.timing.T11(CMD_WREG))
 
-	adc.regLW[regAddr] = value
+	copy(adc.regLW[start:], values)
+	if adc.regLW[REG_STATUS]&STATUS_ACAL != 0 {
+		// with ACAL set, writing BUFEN (STATUS), PGA (ADCON) or DRATE starts a self-calibration
+		for _, reg := range []byte{REG_STATUS, REG_ADCON, REG_DRATE} {
+			if reg >= start && int(reg) < int(start)+len(values) {
+				adc.calPending.Store(true)
+			}
+		}
+	}
 	return adc.setCSHigh()
 }
 
 // readRegister reads a single register [regAddr].
 func (adc *ADS1256) readRegister(regAddr byte) (byte, error) {
-	if regAddr >= NumRegisters {
-		return 0, fmt.Errorf("invalid register address 0x%02X", regAddr)
+	vals, err := adc.readRegisters(regAddr, 1)
+	if err != nil {
+		return 0, err
+	}
+	return vals[0], nil
+}
+
+// readRegisters reads [count] consecutive registers starting at [start] with one RREG command.
+// The returned slice aliases the "last read" register cache and is only valid while adc.mu is held.
+func (adc *ADS1256) readRegisters(start byte, count int) (_ []byte, err error) {
+	defer func() { err = registerError(OP_READ_REGISTER, start, err) }()
+	if err := checkRegisterRange(start, count); err != nil {
+		return nil, err
 	}
 
 	if err := adc.setCSLow(); err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	// If in continuous read mode, must send SDATAC first
-	if adc.continuousMode.Load() {
-		if _, err := adc.Write([]byte{CMD_SDATAC}); err != nil {
-			return 0, err
-		}
-		adc.continuousMode.Store(false)
-		time.Sleep(100 * time.Microsecond)
+	if err := adc.exitContinuous(); err != nil {
+		return nil, errors.Join(err, adc.setCSHigh())
 	}
 
-	// RREG: 0x10 + regAddr
-	cmd := CMD_RREG | (regAddr & 0x0F)
-
-	// 2nd byte => # of registers -1 => 0
-	out := []byte{cmd, 0x00}
+	// RREG: 0x10 + start, then # of registers - 1
+	out := []byte{CMD_RREG | (start & 0x0F), byte(count - 1)}
 	if _, err := adc.Write(out); err != nil {
-		return 0, err
+		return nil, errors.Join(err, adc.setCSHigh())
 	}
 
-	time.Sleep(50 * time.Microsecond)
+	delay(adc.timing.T6())
 
-	// read single register
-	buf := get1Byte()
-
-	_, err := adc.Read(buf)
-
-	if err != nil {
-		put1Byte(buf)
-		return 0, err
+	vals := adc.regLR[start : int(start)+count]
+	var buf [NumRegisters]byte
+	if _, err := adc.Read(buf[:count]); err != nil {
+		return nil, errors.Join(err, adc.setCSHigh())
 	}
+	copy(vals, buf[:count])
+	delay(adc.timing.T11(CMD_RREG))
 
-	copy(adc.regLR[regAddr:], buf)
-
-	put1Byte(buf)
-	return adc.regLR[regAddr], nil
+	return vals, adc.setCSHigh()
 }
 
 func (adc *ADS1256) ReadAllRegisters() (registers map[Register]byte, err error) {
@@ -119,13 +184,6 @@ func (adc *ADS1256) ReadAllRegisters() (registers map[Register]byte, err error)
 
 // readAllRegisters is optional, but can be handy for debug
 func (adc *ADS1256) readAllRegisters() error {
-	for reg := byte(0); reg < NumRegisters; reg++ {
-		val, err := adc.readRegister(reg)
-		if err != nil {
-			return err
-		}
-		adc.regLR[reg] = val // done in readRegister
-	}
-
-	return nil
+	_, err := adc.readRegisters(REG_STATUS, NumRegisters)
+	return err
 }
@@ -0,0 +1,348 @@
+package ads1256
+
+import (
+	"fmt"
+	"strings"
+)
+
+// This file holds the typed register model. Each register has a struct (or named type)
+// with a lossless Byte/Parse pair, [encoding.BinaryMarshaler] support and a String
+// method that decodes the bits for humans. The driver builds every register value it
+// writes from these types, so the bit layouts live only here.
+
+var registerNames = [NumRegisters]string{
+	REG_STATUS: "STATUS",
+	REG_MUX:    "MUX",
+	REG_ADCON:  "ADCON",
+	REG_DRATE:  "DRATE",
+	REG_IO:     "IO",
+	REG_OFC0:   "OFC0",
+	REG_OFC1:   "OFC1",
+	REG_OFC2:   "OFC2",
+	REG_FSC0:   "FSC0",
+	REG_FSC1:   "FSC1",
+	REG_FSC2:   "FSC2",
+}
+
+func (r Register) String() string {
+	if r >= NumRegisters {
+		return fmt.Sprintf("(invalid register 0x%02X)", byte(r))
+	}
+	return registerNames[r]
+}
+
+func marshalByte(b byte) ([]byte, error) {
+	return []byte{b}, nil
+}
+
+func unmarshalByte(r Register, data []byte) (byte, error) {
+	if len(data) != 1 {
+		return 0, fmt.Errorf("%w: %s: expected 1 byte, got %d", ErrInvalidSetting, r, len(data))
+	}
+	return data[0], nil
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// Status is the STATUS register (0x00).
+type Status struct {
+	ID    byte // Factory programmed identification bits (7:4), read-only
+	Order bool // Output data bit order: false = MSB first, true = LSB first
+	ACal  bool // Auto-calibration after changes to PGA, DRATE or BUFEN
+	BufEn bool // Analog input buffer enable
+	DRDY  bool // Mirrors the DRDY pin: true while data is NOT ready, read-only
+}
+
+// ParseStatus decodes a raw STATUS register value.
+func ParseStatus(b byte) Status {
+	return Status{
+		ID:    b >> 4,
+		Order: b&STATUS_ORDER != 0,
+		ACal:  b&STATUS_ACAL != 0,
+		BufEn: b&STATUS_BUFEN != 0,
+		DRDY:  b&STATUS_DRDY != 0,
+	}
+}
+
+// Byte encodes the register value.
+func (s Status) Byte() byte {
+	b := (s.ID & 0x0F) << 4
+	if s.Order {
+		b |= STATUS_ORDER
+	}
+	if s.ACal {
+		b |= STATUS_ACAL
+	}
+	if s.BufEn {
+		b |= STATUS_BUFEN
+	}
+	if s.DRDY {
+		b |= STATUS_DRDY
+	}
+	return b
+}
+
+func (s Status) MarshalBinary() ([]byte, error) {
+	return marshalByte(s.Byte())
+}
+
+func (s *Status) UnmarshalBinary(data []byte) error {
+	b, err := unmarshalByte(REG_STATUS, data)
+	*s = ParseStatus(b)
+	return err
+}
+
+func (s Status) String() string {
+	order := "MSB"
+	if s.Order {
+		order = "LSB"
+	}
+	drdy := "ready"
+	if s.DRDY {
+		drdy = "busy"
+	}
+	return fmt.Sprintf("STATUS{ID:%d, ORDER:%s first, ACAL:%s, BUFEN:%s, DRDY:%s}",
+		s.ID, order, onOff(s.ACal), onOff(s.BufEn), drdy)
+}
+
+// Mux is the input multiplexer control register (0x01).
+type Mux struct {
+	Pos Channel // Positive input channel (PSEL3:0)
+	Neg Channel // Negative input channel (NSEL3:0)
+}
+
+// ParseMux decodes a raw MUX register value.
+func ParseMux(b byte) Mux {
+	return Mux{Pos: Channel(b >> 4), Neg: Channel(b & 0x0F)}
+}
+
+// Byte encodes the register value.
+func (m Mux) Byte() byte {
+	return (m.Pos.Byte()&0x0F)<<4 | m.Neg.Byte()&0x0F
+}
+
+// Pair returns the channel pair selected by the multiplexer.
+func (m Mux) Pair() ChannelPair {
+	return ChannelPair{Pos: m.Pos, Neg: m.Neg}
+}
+
+func (m Mux) MarshalBinary() ([]byte, error) {
+	return marshalByte(m.Byte())
+}
+
+func (m *Mux) UnmarshalBinary(data []byte) error {
+	b, err := unmarshalByte(REG_MUX, data)
+	*m = ParseMux(b)
+	return err
+}
+
+func (m Mux) String() string {
+	return fmt.Sprintf("MUX{PSEL:%s, NSEL:%s}", m.Pos, m.Neg)
+}
+
+// ADCON is the A/D control register (0x02).
+type ADCON struct {
+	ClkOut byte // CLKOUT rate (CLK1:0): 0=off, 1=fCLKIN, 2=fCLKIN/2, 3=fCLKIN/4
+	SDCS   byte // Sensor detect current (SDCS1:0): 0=off, 1=0.5µA, 2=2µA, 3=10µA
+	PGA    byte // Programmable gain amplifier setting (PGA2:0), see ADCON_PGA_1 and friends
+
+	reserved bool // bit 7, always 0 on a healthy device; kept so decoding is lossless
+}
+
+// ParseADCON decodes a raw ADCON register value.
+func ParseADCON(b byte) ADCON {
+	return ADCON{
+		ClkOut:   (b >> 5) & 0x03,
+		SDCS:     (b >> 3) & 0x03,
+		PGA:      b & 0x07,
+		reserved: b&0x80 != 0,
+	}
+}
+
+// Byte encodes the register value.
+func (a ADCON) Byte() byte {
+	b := (a.ClkOut&0x03)<<5 | (a.SDCS&0x03)<<3 | a.PGA&0x07
+	if a.reserved {
+		b |= 0x80
+	}
+	return b
+}
+
+// Gain returns the amplifier gain selected by the PGA bits.
+func (a ADCON) Gain() int {
+	return 1 << min(a.PGA&0x07, 6)
+}
+
+func (a ADCON) MarshalBinary() ([]byte, error) {
+	return marshalByte(a.Byte())
+}
+
+func (a *ADCON) UnmarshalBinary(data []byte) error {
+	b, err := unmarshalByte(REG_ADCON, data)
+	*a = ParseADCON(b)
+	return err
+}
+
+var (
+	clkOutNames = [4]string{"off", "fCLKIN", "fCLKIN/2", "fCLKIN/4"}
+	sdcsNames   = [4]string{"off", "0.5µA", "2µA", "10µA"}
+)
+
+func (a ADCON) String() string {
+	return fmt.Sprintf("ADCON{CLKOUT:%s, SDCS:%s, PGA:%d}",
+		clkOutNames[a.ClkOut&0x03], sdcsNames[a.SDCS&0x03], a.Gain())
+}
+
+// DRate is the A/D data rate register (0x03). Valid values are the DRATE_DR_* constants.
+type DRate byte
+
+// ParseDRate decodes a raw DRATE register value.
+func ParseDRate(b byte) DRate {
+	return DRate(b)
+}
+
+// Byte encodes the register value.
+func (d DRate) Byte() byte {
+	return byte(d)
+}
+
+func (d DRate) MarshalBinary() ([]byte, error) {
+	return marshalByte(d.Byte())
+}
+
+func (d *DRate) UnmarshalBinary(data []byte) error {
+	b, err := unmarshalByte(REG_DRATE, data)
+	*d = ParseDRate(b)
+	return err
+}
+
+func (d DRate) String() string {
+	sps, ok := dataRates[byte(d)]
+	if !ok {
+		return fmt.Sprintf("DRATE{0x%02X: invalid}", byte(d))
+	}
+	return fmt.Sprintf("DRATE{0x%02X: %g SPS}", byte(d), sps)
+}
+
+// IO is the GPIO control register (0x04) for the digital pins D0..D3.
+type IO struct {
+	Input [4]bool // Pin direction (DIR3:0): true = input, false = output
+	Level [4]bool // Pin state (DIO3:0): level read from or driven onto the pin
+}
+
+// ParseIO decodes a raw IO register value.
+func ParseIO(b byte) IO {
+	var io IO
+	for i := 0; i < 4; i++ {
+		io.Input[i] = b&(0x10<<i) != 0
+		io.Level[i] = b&(0x01<<i) != 0
+	}
+	return io
+}
+
+// Byte encodes the register value.
+func (io IO) Byte() byte {
+	var b byte
+	for i := 0; i < 4; i++ {
+		if io.Input[i] {
+			b |= 0x10 << i
+		}
+		if io.Level[i] {
+			b |= 0x01 << i
+		}
+	}
+	return b
+}
+
+func (io IO) MarshalBinary() ([]byte, error) {
+	return marshalByte(io.Byte())
+}
+
+func (io *IO) UnmarshalBinary(data []byte) error {
+	b, err := unmarshalByte(REG_IO, data)
+	*io = ParseIO(b)
+	return err
+}
+
+func (io IO) String() string {
+	var sb strings.Builder
+	sb.WriteString("IO{")
+	for i := 0; i < 4; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		dir := "out"
+		if io.Input[i] {
+			dir = "in"
+		}
+		level := "low"
+		if io.Level[i] {
+			level = "high"
+		}
+		_, _ = fmt.Fprintf(&sb, "D%d:%s/%s", i, dir, level)
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// decodeOFC decodes the 24-bit signed offset calibration value from OFC0..OFC2 (LSB first).
+func decodeOFC(ofc0, ofc1, ofc2 byte) int32 {
+	return Convert24To32([]byte{ofc2, ofc1, ofc0})
+}
+
+// decodeFSC decodes the 24-bit unsigned full-scale calibration value from FSC0..FSC2 (LSB first).
+func decodeFSC(fsc0, fsc1, fsc2 byte) uint32 {
+	return uint32(fsc0) | uint32(fsc1)<<8 | uint32(fsc2)<<16
+}
+
+// RegisterSet is a decoded snapshot of the whole register file.
+type RegisterSet struct {
+	Status Status
+	Mux    Mux
+	ADCON  ADCON
+	DRate  DRate
+	IO     IO
+	OFC    int32  // Offset calibration, 24-bit signed
+	FSC    uint32 // Full-scale calibration, 24-bit unsigned
+}
+
+// ParseRegisterSet decodes a raw dump of all registers, in address order.
+func ParseRegisterSet(raw [NumRegisters]byte) RegisterSet {
+	return RegisterSet{
+		Status: ParseStatus(raw[REG_STATUS]),
+		Mux:    ParseMux(raw[REG_MUX]),
+		ADCON:  ParseADCON(raw[REG_ADCON]),
+		DRate:  ParseDRate(raw[REG_DRATE]),
+		IO:     ParseIO(raw[REG_IO]),
+		OFC:    decodeOFC(raw[REG_OFC0], raw[REG_OFC1], raw[REG_OFC2]),
+		FSC:    decodeFSC(raw[REG_FSC0], raw[REG_FSC1], raw[REG_FSC2]),
+	}
+}
+
+// Bytes encodes the register set back into raw register values, in address order.
+func (rs RegisterSet) Bytes() [NumRegisters]byte {
+	ofc, fsc := uint32(rs.OFC), rs.FSC
+	return [NumRegisters]byte{
+		REG_STATUS: rs.Status.Byte(),
+		REG_MUX:    rs.Mux.Byte(),
+		REG_ADCON:  rs.ADCON.Byte(),
+		REG_DRATE:  rs.DRate.Byte(),
+		REG_IO:     rs.IO.Byte(),
+		REG_OFC0:   byte(ofc),
+		REG_OFC1:   byte(ofc >> 8),
+		REG_OFC2:   byte(ofc >> 16),
+		REG_FSC0:   byte(fsc),
+		REG_FSC1:   byte(fsc >> 8),
+		REG_FSC2:   byte(fsc >> 16),
+	}
+}
+
+func (rs RegisterSet) String() string {
+	return fmt.Sprintf("%s %s %s %s %s OFC:%d FSC:0x%06X",
+		rs.Status, rs.Mux, rs.ADCON, rs.DRate, rs.IO, rs.OFC, rs.FSC)
+}
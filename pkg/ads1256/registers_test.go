@@ -0,0 +1,76 @@
+package ads1256
+
+import (
+	"testing"
+)
+
+func TestRegisterRoundTrip(t *testing.T) {
+	for i := 0; i < 256; i++ {
+		b := byte(i)
+		if got := ParseStatus(b).Byte(); got != b {
+			t.Errorf("STATUS: expected 0x%02X, got 0x%02X", b, got)
+		}
+		if got := ParseMux(b).Byte(); got != b {
+			t.Errorf("MUX: expected 0x%02X, got 0x%02X", b, got)
+		}
+		if got := ParseADCON(b).Byte(); got != b {
+			t.Errorf("ADCON: expected 0x%02X, got 0x%02X", b, got)
+		}
+		if got := ParseDRate(b).Byte(); got != b {
+			t.Errorf("DRATE: expected 0x%02X, got 0x%02X", b, got)
+		}
+		if got := ParseIO(b).Byte(); got != b {
+			t.Errorf("IO: expected 0x%02X, got 0x%02X", b, got)
+		}
+	}
+
+	t.Run("BinaryMarshaler", func(t *testing.T) {
+		var adcon ADCON
+		if err := adcon.UnmarshalBinary([]byte{0x35}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if adcon.ClkOut != 1 || adcon.SDCS != 2 || adcon.PGA != ADCON_PGA_32 {
+			t.Errorf("unexpected decode: %+v", adcon)
+		}
+		data, err := adcon.MarshalBinary()
+		if err != nil || len(data) != 1 || data[0] != 0x35 {
+			t.Errorf("expected [0x35], got % X (err: %v)", data, err)
+		}
+		if err = adcon.UnmarshalBinary([]byte{0x01, 0x02}); err == nil {
+			t.Error("expected error for wrong length")
+		}
+	})
+
+	t.Run("RegisterSet", func(t *testing.T) {
+		raw := [NumRegisters]byte{0x31, 0x08, 0x20, DRATE_DR_1000_SPS, 0xE0, 0x39, 0x30, 0xFF, 0x08, 0xAC, 0x44}
+		rs := ParseRegisterSet(raw)
+		if rs.OFC != -53191 {
+			t.Errorf("OFC: expected -53191, got %d", rs.OFC)
+		}
+		if rs.FSC != 0x44AC08 {
+			t.Errorf("FSC: expected 0x44AC08, got 0x%06X", rs.FSC)
+		}
+		if rs.Bytes() != raw {
+			t.Errorf("expected % X, got % X", raw, rs.Bytes())
+		}
+	})
+}
+
+func TestRegisterString(t *testing.T) {
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{ParseStatus(0x36).String(), "STATUS{ID:3, ORDER:MSB first, ACAL:on, BUFEN:on, DRDY:ready}"},
+		{ParseMux(0x08).String(), "MUX{PSEL:CH_AIN0, NSEL:CH_AINCOM}"},
+		{ParseADCON(0x24).String(), "ADCON{CLKOUT:fCLKIN, SDCS:off, PGA:16}"},
+		{ParseDRate(DRATE_DR_2000_SPS).String(), "DRATE{0xB0: 2000 SPS}"},
+		{ParseIO(0xE1).String(), "IO{D0:out/high, D1:in/low, D2:in/low, D3:in/low}"},
+		{Register(REG_FSC2).String(), "FSC2"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, tt.got)
+		}
+	}
+}
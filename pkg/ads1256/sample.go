@@ -0,0 +1,92 @@
+package ads1256
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Sample is a single conversion result together with the context it was taken in.
+//
+// Sequence numbers are kept per ADS1256 and shared by every acquisition path, so a gap in
+// ChanSeq between two samples of the same pair received by one consumer means that a
+// conversion of that pair was delivered elsewhere (or not at all).
+type Sample struct {
+	Pair ChannelPair
+
+	// Time is the host time at which DRDY was observed for this conversion. It carries a
+	// monotonic clock reading, so intervals between samples are immune to wall clock changes.
+	Time time.Time
+
+	Seq     uint64 // Global sequence number, incremented for every sample of any pair (starts at 1)
+	ChanSeq uint64 // Sequence number within Pair (starts at 1)
+	Cycle   uint64 // Index of the scan pass that produced the sample (starts at 1); 0 outside scans
+
+	Code  int32   // Raw signed 24-bit conversion result
+	Value Voltage // Code converted with the reference voltage and PGA that were active
+
+	PGA      byte // ADCON_PGA_* setting the sample was acquired with
+	DataRate byte // DRATE_DR_* setting the sample was acquired with
+}
+
+func (s Sample) String() string {
+	return fmt.Sprintf("#%d %s/%s #%d: %d (%s)", s.Seq, s.Pair.Pos, s.Pair.Neg, s.ChanSeq, s.Code, s.Value)
+}
+
+// SampleCallback receives every [Sample] produced by a channel scan.
+type SampleCallback func(s Sample)
+
+// newSample numbers and converts a result of pair. The settings come from pair.Settings if it has
+// any, otherwise from the registers as last written. The caller must hold adc.mu.
+func (adc *ADS1256) newSample(pair ChannelPair, code int32, ready time.Time, cycle uint64) Sample {
+	pga := ParseADCON(adc.regLW[REG_ADCON]).PGA
+	drate := adc.regLW[REG_DRATE]
+	if pair.Settings != nil {
+		pga = pair.Settings.PGA
+		drate = pair.Settings.DataRate
+	}
+
+	if adc.chanSeq == nil {
+		adc.chanSeq = make(map[byte]uint64)
+	}
+	mux := Mux{Pos: pair.Pos, Neg: pair.Neg}.Byte()
+	adc.seq++
+	adc.chanSeq[mux]++
+
+	return Sample{
+		Pair:     pair,
+		Time:     ready,
+		Seq:      adc.seq,
+		ChanSeq:  adc.chanSeq[mux],
+		Cycle:    cycle,
+		Code:     code,
+		Value:    CodeToVolts(code, adc.vRef(), pga),
+		PGA:      pga,
+		DataRate: drate,
+	}
+}
+
+// ReadChannelSample is [ADS1256.ReadChannel], returning a [Sample].
+func (adc *ADS1256) ReadChannelSample(ainP, ainN Channel) (Sample, error) {
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+	pair := ChannelPair{Pos: ainP, Neg: ainN}
+	code, ready, err := adc.readPairAt(context.Background(), pair)
+	if err != nil {
+		return Sample{}, err
+	}
+	return adc.newSample(pair, code, ready, 0), nil
+}
+
+// SingleConversionSample is [ADS1256.SingleConversion], returning a [Sample] of the channel
+// pair currently selected in the multiplexer.
+func (adc *ADS1256) SingleConversionSample() (Sample, error) {
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+	code, ready, err := adc.singleConversion(context.Background())
+	if err != nil {
+		return Sample{}, err
+	}
+	mux := ParseMux(adc.regLW[REG_MUX])
+	return adc.newSample(ChannelPair{Pos: mux.Pos, Neg: mux.Neg}, code, ready, 0), nil
+}
@@ -0,0 +1,96 @@
+package ads1256
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// OverrunPolicy selects what a fixed-rate scan does when a pass is still running at the time
+// the next one should start (see [ScanOptions.Period]).
+type OverrunPolicy int
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	// OVERRUN_SKIP drops the missed starts and waits for the next start time on the timeline.
+	// Every pass still starts on the timeline, at the cost of gaps in the data.
+	OVERRUN_SKIP OverrunPolicy = iota
+	// OVERRUN_CATCH_UP starts missed passes immediately, back to back, until the scan is back
+	// on the timeline. No pass is dropped, but the late ones are not evenly spaced.
+	OVERRUN_CATCH_UP
+)
+
+func (p OverrunPolicy) String() string {
+	switch p {
+	case OVERRUN_SKIP:
+		return "skip"
+	case OVERRUN_CATCH_UP:
+		return "catch-up"
+	default:
+		return "(invalid overrun policy)"
+	}
+}
+
+// DeadlineMiss reports a pass of a fixed-rate scan that did not start on time.
+type DeadlineMiss struct {
+	Cycle    uint64        // Cycle of the first pass that missed its start time
+	Deadline time.Time     // When that pass should have started
+	Late     time.Duration // How far past Deadline the previous pass ended
+	Skipped  uint64        // Passes dropped under OVERRUN_SKIP; 0 under OVERRUN_CATCH_UP
+}
+
+func (m DeadlineMiss) String() string {
+	return fmt.Sprintf("cycle %d: %s late, %d skipped", m.Cycle, m.Late, m.Skipped)
+}
+
+// MissCallback receives every [DeadlineMiss] of a fixed-rate scan. It is called from the scan
+// goroutine between passes, without the ADC lock.
+type MissCallback func(m DeadlineMiss)
+
+// schedule places the passes of a fixed-rate scan on an absolute timeline: pass n (counting
+// from 1) is due at start + (n-1)*period, independent of how long earlier passes took.
+type schedule struct {
+	start  time.Time
+	period time.Duration
+	policy OverrunPolicy
+}
+
+// due returns when the pass of cycle should start.
+func (s schedule) due(cycle uint64) time.Time {
+	return s.start.Add(time.Duration(cycle-1) * s.period)
+}
+
+// next returns the cycle to run after cycle at time now, and the miss to report if the
+// scan has fallen behind the timeline.
+func (s schedule) next(cycle uint64, now time.Time) (uint64, *DeadlineMiss) {
+	cycle++
+	deadline := s.due(cycle)
+	late := now.Sub(deadline)
+	if late <= 0 {
+		return cycle, nil
+	}
+	miss := &DeadlineMiss{Cycle: cycle, Deadline: deadline, Late: late}
+	if s.policy == OVERRUN_SKIP {
+		// skip to the first start time that is not in the past
+		miss.Skipped = uint64(late / s.period)
+		if late%s.period != 0 {
+			miss.Skipped++
+		}
+		cycle += miss.Skipped
+	}
+	return cycle, miss
+}
+
+// resync returns the first cycle at or after cycle that is not due before now. Used after a
+// pause, whose missed starts are not deadline misses.
+func (s schedule) resync(cycle uint64, now time.Time) uint64 {
+	if late := now.Sub(s.due(cycle)); late > 0 {
+		cycle += uint64((late + s.period - 1) / s.period)
+	}
+	return cycle
+}
+
+// sleepUntil waits until t or until ctx is done.
+func sleepUntil(ctx context.Context, t time.Time) error {
+	return sleepCtx(ctx, time.Until(t))
+}
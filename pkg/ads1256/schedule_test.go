@@ -0,0 +1,96 @@
+package ads1256
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	start := time.Now()
+	ms := time.Millisecond
+
+	tests := []struct {
+		name   string
+		policy OverrunPolicy
+		cycle  uint64
+		now    time.Duration // since start
+		next   uint64
+		miss   *DeadlineMiss
+	}{
+		{"OnTime", OVERRUN_SKIP, 1, 7 * ms, 2, nil},
+		{"Exact", OVERRUN_SKIP, 1, 10 * ms, 2, nil},
+		{"SkipOne", OVERRUN_SKIP, 1, 13 * ms, 3, &DeadlineMiss{Cycle: 2, Late: 3 * ms, Skipped: 1}},
+		{"SkipMany", OVERRUN_SKIP, 4, 65 * ms, 8, &DeadlineMiss{Cycle: 5, Late: 25 * ms, Skipped: 3}},
+		{"SkipToExact", OVERRUN_SKIP, 1, 30 * ms, 4, &DeadlineMiss{Cycle: 2, Late: 20 * ms, Skipped: 2}},
+		{"CatchUp", OVERRUN_CATCH_UP, 4, 65 * ms, 5, &DeadlineMiss{Cycle: 5, Late: 25 * ms}},
+	}
+	for _, tt := range tests {
+		s := schedule{start: start, period: 10 * ms, policy: tt.policy}
+		next, miss := s.next(tt.cycle, start.Add(tt.now))
+		if next != tt.next {
+			t.Errorf("%s: expected cycle %d, got %d", tt.name, tt.next, next)
+		}
+		if tt.miss == nil {
+			if miss != nil {
+				t.Errorf("%s: unexpected %s", tt.name, miss)
+			}
+			continue
+		}
+		tt.miss.Deadline = s.due(tt.miss.Cycle)
+		if miss == nil || *miss != *tt.miss {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.miss, miss)
+		}
+		if due := s.due(next); next != tt.cycle+1 && due.Before(start.Add(tt.now)) {
+			t.Errorf("%s: skipped to cycle %d, which is due in the past", tt.name, next)
+		}
+	}
+
+	// run passes of the given durations on a virtual clock, as the scan loop does
+	timeline := func(policy OverrunPolicy, durations ...time.Duration) (cycles []uint64, misses []DeadlineMiss) {
+		s := schedule{start: start, period: 10 * ms, policy: policy}
+		now, cycle := start, uint64(1)
+		for _, d := range durations {
+			if due := s.due(cycle); now.Before(due) {
+				now = due
+			}
+			cycles = append(cycles, cycle)
+			now = now.Add(d)
+
+			var miss *DeadlineMiss
+			if cycle, miss = s.next(cycle, now); miss != nil {
+				misses = append(misses, *miss)
+			}
+		}
+		return cycles, misses
+	}
+
+	cycles, misses := timeline(OVERRUN_SKIP, 5*ms, 5*ms, 35*ms, 5*ms, 5*ms)
+	if want := []uint64{1, 2, 3, 7, 8}; !slices.Equal(cycles, want) {
+		t.Errorf("Timeline/Skip: expected cycles %v, got %v", want, cycles)
+	}
+	if want := []DeadlineMiss{{Cycle: 4, Deadline: start.Add(30 * ms), Late: 25 * ms, Skipped: 3}}; !slices.Equal(misses, want) {
+		t.Errorf("Timeline/Skip: expected misses %v, got %v", want, misses)
+	}
+
+	cycles, misses = timeline(OVERRUN_CATCH_UP, 5*ms, 5*ms, 35*ms, 5*ms, 5*ms, 5*ms, 5*ms, 5*ms, 5*ms)
+	if want := []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9}; !slices.Equal(cycles, want) {
+		t.Errorf("Timeline/CatchUp: expected cycles %v, got %v", want, cycles)
+	}
+	var late []time.Duration
+	for _, m := range misses {
+		late = append(late, m.Late)
+	}
+	// each pass after the overrun starts back to back and gains 5ms on the timeline
+	if want := []time.Duration{25 * ms, 20 * ms, 15 * ms, 10 * ms, 5 * ms}; !slices.Equal(late, want) || misses[0].Cycle != 4 {
+		t.Errorf("Timeline/CatchUp: expected misses from cycle 4, %v late, got %v", want, misses)
+	}
+
+	s := schedule{start: start, period: 10 * ms}
+	if got := s.resync(3, start.Add(41*ms)); got != 6 {
+		t.Errorf("resync: expected cycle 6, got %d", got)
+	}
+	if got := s.resync(3, start.Add(5*ms)); got != 3 {
+		t.Errorf("resync: expected cycle 3, got %d", got)
+	}
+}
@@ -0,0 +1,145 @@
+package ads1256
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// sdcsField converts one of the ADCON_SDCS_* constants to the value of the [ADCON] SDCS field.
+func sdcsField(sdcs byte) byte {
+	return (sdcs >> 3) & 0x03
+}
+
+// sensorCurrents are the sensor-detect currents in amps, indexed by the [ADCON] SDCS field.
+var sensorCurrents = [4]float64{0, 0.5e-6, 2e-6, 10e-6}
+
+func checkSDCS(sdcs byte) error {
+	switch sdcs {
+	case ADCON_SDCS_OFF, ADCON_SDCS_0p5uA, ADCON_SDCS_2uA, ADCON_SDCS_10uA:
+		return nil
+	default:
+		return fmt.Errorf("%w: sensor detect current 0x%02X", ErrInvalidSetting, sdcs)
+	}
+}
+
+// SetSensorDetect changes the sensor-detect current source at runtime.
+// sdcs is one of ADCON_SDCS_OFF, ADCON_SDCS_0p5uA, ADCON_SDCS_2uA or ADCON_SDCS_10uA.
+func (adc *ADS1256) SetSensorDetect(sdcs byte) error {
+	if err := checkSDCS(sdcs); err != nil {
+		return err
+	}
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+	if err := adc.setSDCS(sdcs); err != nil {
+		return err
+	}
+	adc.cfg.SensorDetect = sdcs
+	return nil
+}
+
+func (adc *ADS1256) setSDCS(sdcs byte) error {
+	adcon := ParseADCON(adc.regLW[REG_ADCON])
+	adcon.SDCS = sdcsField(sdcs)
+	return adc.writeRegister(REG_ADCON, adcon.Byte())
+}
+
+// InputState is the outcome of [ADS1256.DetectOpenInput].
+type InputState int
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	INPUT_CONNECTED InputState = iota // A real source is connected
+	INPUT_OPEN                        // The input is open circuit (e.g. a broken wire)
+	INPUT_SHORTED                     // The input reads zero, and the bias current develops no voltage across it
+)
+
+func (s InputState) String() string {
+	switch s {
+	case INPUT_CONNECTED:
+		return "connected"
+	case INPUT_OPEN:
+		return "open"
+	case INPUT_SHORTED:
+		return "shorted"
+	default:
+		return "(invalid input state)"
+	}
+}
+
+// Thresholds used to classify sensor-detect measurements.
+const (
+	// openThreshold is the biased reading, in codes, above which an input is considered open. The
+	// current source drives an open input to the supply, so it reads at (or within 1% of)
+	// positive full scale at any gain.
+	openThreshold = (1<<23 - 1) - (1<<23)/100
+	// shortVolts is the input voltage below which the unbiased reading must stay for an input
+	// to be considered shorted.
+	shortVolts = 1e-3
+	// shortOhms is the source resistance below which an input is considered shorted. The bias
+	// current develops I·R across the source, so a thermocouple or other low-resistance sensor
+	// of a few ohms still shifts the biased reading where a short does not.
+	shortOhms = 1.0
+)
+
+// OpenInputResult holds the measurements taken by [ADS1256.DetectOpenInput].
+type OpenInputResult struct {
+	Pair   ChannelPair
+	State  InputState
+	Code   int32 // Reading with the sensor-detect current off
+	Biased int32 // Reading with the sensor-detect current on
+}
+
+// DetectOpenInput checks a channel pair for an open or shorted input. It measures the pair with
+// the sensor-detect current off and then on, and classifies the input from the two readings:
+// open if the biased reading is at positive full scale, shorted if the unbiased reading is
+// near zero and the bias current shifts it by less than it would across [shortOhms], and
+// connected otherwise. The thresholds are converted to codes at the programmed PGA; at low
+// currents and high data rates, conversion noise can exceed the shift of a short.
+//
+// The current configured in [Config.SensorDetect] is used, or 2 µA if sensor detect is off.
+// The sensor-detect setting is restored before returning.
+func (adc *ADS1256) DetectOpenInput(pair ChannelPair) (OpenInputResult, error) {
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+
+	res := OpenInputResult{Pair: pair}
+
+	sdcs := adc.cfg.SensorDetect
+	if sdcs == ADCON_SDCS_OFF {
+		sdcs = ADCON_SDCS_2uA
+	}
+
+	var err error
+	if err = adc.setSDCS(ADCON_SDCS_OFF); err != nil {
+		return res, err
+	}
+	if res.Code, err = adc.readChannel(context.Background(), pair.Pos, pair.Neg); err != nil {
+		return res, errors.Join(err, adc.setSDCS(adc.cfg.SensorDetect))
+	}
+
+	if err = adc.setSDCS(sdcs); err != nil {
+		return res, errors.Join(err, adc.setSDCS(adc.cfg.SensorDetect))
+	}
+	if res.Biased, err = adc.readChannel(context.Background(), pair.Pos, pair.Neg); err != nil {
+		return res, errors.Join(err, adc.setSDCS(adc.cfg.SensorDetect))
+	}
+
+	if err = adc.setSDCS(adc.cfg.SensorDetect); err != nil {
+		return res, err
+	}
+
+	lsb := LSBVolts(adc.vRef(), ParseADCON(adc.regLW[REG_ADCON]).PGA)
+	shift := float64(res.Biased-res.Code) * lsb
+	switch {
+	case res.Biased >= openThreshold:
+		res.State = INPUT_OPEN
+	case math.Abs(float64(res.Code)*lsb) < shortVolts && math.Abs(shift) < sensorCurrents[sdcsField(sdcs)]*shortOhms:
+		res.State = INPUT_SHORTED
+	default:
+		res.State = INPUT_CONNECTED
+	}
+
+	return res, nil
+}
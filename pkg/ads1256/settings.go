@@ -0,0 +1,97 @@
+package ads1256
+
+import "fmt"
+
+// AcquisitionSettings are the conversion settings used for one channel pair in a scan.
+// Attach them to a [ChannelPair] to override the values from [Config] for that pair.
+type AcquisitionSettings struct {
+	PGA          byte // e.g. ADCON_PGA_1, ADCON_PGA_2, ...
+	DataRate     byte // DR_xxx from the set of DRATE_DR_XXXX_SPS
+	BufferEn     bool // Enable the ADC's internal buffer
+	SensorDetect byte // ADCON_SDCS_OFF, ADCON_SDCS_0p5uA, ADCON_SDCS_2uA or ADCON_SDCS_10uA
+}
+
+// Validate checks that every field holds a value the ADS1256 accepts.
+func (s AcquisitionSettings) Validate() error {
+	if s.PGA > 0x07 {
+		return fmt.Errorf("%w: PGA 0x%02X", ErrInvalidSetting, s.PGA)
+	}
+	if _, ok := dataRates[s.DataRate]; !ok {
+		return fmt.Errorf("%w: data rate 0x%02X", ErrInvalidSetting, s.DataRate)
+	}
+	return checkSDCS(s.SensorDetect)
+}
+
+func (s AcquisitionSettings) String() string {
+	return fmt.Sprintf("{PGA:%d, %s, BUFEN:%s, SDCS:%s}",
+		ADCON{PGA: s.PGA}.Gain(), DRate(s.DataRate), onOff(s.BufferEn), sdcsNames[sdcsField(s.SensorDetect)])
+}
+
+// Settings returns the acquisition settings described by the configuration.
+func (cfg Config) Settings() AcquisitionSettings {
+	return AcquisitionSettings{
+		PGA:          cfg.PGA,
+		DataRate:     cfg.DataRate,
+		BufferEn:     cfg.BufferEn,
+		SensorDetect: cfg.SensorDetect,
+	}
+}
+
+// resolvePairs returns a copy of pairs in which every pair carries the settings it will be
+// acquired with: its own if it has any, otherwise those from the configuration.
+func (adc *ADS1256) resolvePairs(pairs []ChannelPair) ([]ChannelPair, error) {
+	adc.mu.RLock()
+	defaults := adc.cfg.Settings()
+	adc.mu.RUnlock()
+
+	resolved := make([]ChannelPair, len(pairs))
+	for i, pair := range pairs {
+		settings := defaults
+		if pair.Settings != nil {
+			settings = *pair.Settings
+		}
+		if err := settings.Validate(); err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", pair.Pos, pair.Neg, err)
+		}
+		pair.Settings = &settings
+		resolved[i] = pair
+	}
+	return resolved, nil
+}
+
+// applySettings programs STATUS, ADCON and DRATE for s, writing only the registers whose
+// value actually changes.
+func (adc *ADS1256) applySettings(s AcquisitionSettings) error {
+	status := ParseStatus(adc.regLW[REG_STATUS])
+	status.BufEn = s.BufferEn
+	if b := status.Byte(); b != adc.regLW[REG_STATUS] {
+		if err := adc.writeRegister(REG_STATUS, b); err != nil {
+			return err
+		}
+	}
+
+	adcon := ParseADCON(adc.regLW[REG_ADCON])
+	adcon.PGA = s.PGA
+	adcon.SDCS = sdcsField(s.SensorDetect)
+	if b := adcon.Byte(); b != adc.regLW[REG_ADCON] {
+		if err := adc.writeRegister(REG_ADCON, b); err != nil {
+			return err
+		}
+	}
+
+	if s.DataRate != adc.regLW[REG_DRATE] {
+		if err := adc.writeRegister(REG_DRATE, s.DataRate); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// settingsChanged reports whether applying s would rewrite any register.
+func (adc *ADS1256) settingsChanged(s AcquisitionSettings) bool {
+	status := ParseStatus(adc.regLW[REG_STATUS])
+	adcon := ParseADCON(adc.regLW[REG_ADCON])
+	return status.BufEn != s.BufferEn || adcon.PGA != s.PGA ||
+		adcon.SDCS != sdcsField(s.SensorDetect) || adc.regLW[REG_DRATE] != s.DataRate
+}
@@ -0,0 +1,306 @@
+package ads1256
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// intervalBounds are the upper bounds of the [IntervalHistogram] buckets, in a 1-2-5 series.
+var intervalBounds = []time.Duration{
+	100 * time.Microsecond, 200 * time.Microsecond, 500 * time.Microsecond,
+	time.Millisecond, 2 * time.Millisecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 20 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 200 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2 * time.Second, 5 * time.Second,
+}
+
+// IntervalHistogram counts intervals between consecutive samples of a channel pair.
+// Counts[i] holds the intervals shorter than Bounds[i] and not shorter than Bounds[i-1];
+// the last count holds everything from the last bound up.
+type IntervalHistogram struct {
+	Bounds []time.Duration `json:"bounds"`
+	Counts []uint64        `json:"counts"`
+}
+
+func newIntervalHistogram() IntervalHistogram {
+	return IntervalHistogram{Bounds: intervalBounds, Counts: make([]uint64, len(intervalBounds)+1)}
+}
+
+func (h *IntervalHistogram) add(d time.Duration) {
+	i := 0
+	for i < len(h.Bounds) && d >= h.Bounds[i] {
+		i++
+	}
+	h.Counts[i]++
+}
+
+func (h IntervalHistogram) clone() IntervalHistogram {
+	return IntervalHistogram{Bounds: h.Bounds, Counts: append([]uint64(nil), h.Counts...)}
+}
+
+// DurationStats summarises a series of durations.
+type DurationStats struct {
+	Count uint64        `json:"count"`
+	Total time.Duration `json:"total"`
+	Min   time.Duration `json:"min"`
+	Max   time.Duration `json:"max"`
+}
+
+func (d *DurationStats) add(v time.Duration) {
+	if d.Count == 0 || v < d.Min {
+		d.Min = v
+	}
+	if v > d.Max {
+		d.Max = v
+	}
+	d.Count++
+	d.Total += v
+}
+
+// Mean returns the average duration, or 0 if there are none.
+func (d DurationStats) Mean() time.Duration {
+	if d.Count == 0 {
+		return 0
+	}
+	return d.Total / time.Duration(d.Count)
+}
+
+func (d DurationStats) String() string {
+	return fmt.Sprintf("mean %s [%s..%s]", d.Mean(), d.Min, d.Max)
+}
+
+// ErrorCounts counts scan errors by class (see [ErrTransport], [ErrConfig] and [ErrDevice]).
+type ErrorCounts struct {
+	Transport uint64 `json:"transport"`
+	Config    uint64 `json:"config"`
+	Device    uint64 `json:"device"`
+	Other     uint64 `json:"other"`
+}
+
+func (e *ErrorCounts) add(err error) {
+	switch {
+	case errors.Is(err, ErrTransport):
+		e.Transport++
+	case errors.Is(err, ErrConfig):
+		e.Config++
+	case errors.Is(err, ErrDevice):
+		e.Device++
+	default:
+		e.Other++
+	}
+}
+
+// Total returns the number of errors of any class.
+func (e ErrorCounts) Total() uint64 {
+	return e.Transport + e.Config + e.Device + e.Other
+}
+
+// PairStats are the counters of one channel pair in a [ScanStats] snapshot.
+type PairStats struct {
+	Pair    ChannelPair `json:"-"`
+	Name    string      `json:"pair"`    // e.g. "CH_AIN0/CH_AINCOM"
+	Samples uint64      `json:"samples"` // Results delivered
+	Dropped uint64      `json:"dropped"` // Passes that ended without a result for the pair
+	SPS     float64     `json:"sps"`     // Effective rate between the first and the last result
+
+	Interval  DurationStats     `json:"interval"`  // Time between consecutive results
+	Jitter    time.Duration     `json:"jitter"`    // Standard deviation of Interval
+	Histogram IntervalHistogram `json:"histogram"` // Distribution of Interval
+	Latency   DurationStats     `json:"latency"`   // Time from DRDY to the result reaching the callbacks
+}
+
+// ScanStats is a snapshot of the performance counters of a [ChannelScan].
+//
+// Intervals spanning a pause are not counted; Elapsed and SPS include paused time.
+type ScanStats struct {
+	Start   time.Time     `json:"start"`
+	Elapsed time.Duration `json:"elapsed"`
+	Cycles  uint64        `json:"cycles"`  // Passes in which every pair produced a result
+	Samples uint64        `json:"samples"` // Results of all pairs
+	SPS     float64       `json:"sps"`     // Results of all pairs per second since Start
+
+	Missed  uint64 `json:"missed"`  // Deadline misses of a fixed-rate scan (see [ScanOptions.Period])
+	Skipped uint64 `json:"skipped"` // Passes dropped by OVERRUN_SKIP
+
+	DRDYWait DurationStats `json:"drdy_wait"` // Time spent waiting for DRDY, per result
+	SPI      DurationStats `json:"spi"`       // Time spent on SPI and CS transfers, per result
+
+	Errors ErrorCounts `json:"errors"`
+	Pairs  []PairStats `json:"pairs"`
+}
+
+func (s ScanStats) String() string {
+	return fmt.Sprintf("%d cycles, %d samples in %s (%.1f SPS), DRDY %s, SPI %s, %d missed, %d errors",
+		s.Cycles, s.Samples, s.Elapsed.Round(time.Millisecond), s.SPS, s.DRDYWait, s.SPI, s.Missed, s.Errors.Total())
+}
+
+// pairCounters accumulates the statistics of one channel pair.
+type pairCounters struct {
+	stats     PairStats
+	first     time.Time
+	last      time.Time // Zero after a pause, so the gap is not counted as an interval
+	lastCycle uint64
+	mean, m2  float64 // Welford's running mean and sum of squares of Interval, in seconds
+}
+
+// scanCounters accumulates the statistics of a [ChannelScan].
+type scanCounters struct {
+	mu    sync.Mutex
+	stats ScanStats
+	pairs map[byte]*pairCounters // keyed by MUX value
+	order []byte
+
+	lastDRDY, lastSPI time.Duration // adc totals at the previous result
+}
+
+func newScanCounters(pairs []ChannelPair) *scanCounters {
+	c := &scanCounters{pairs: make(map[byte]*pairCounters, len(pairs))}
+	for _, pair := range pairs {
+		mux := Mux{Pos: pair.Pos, Neg: pair.Neg}.Byte()
+		if _, ok := c.pairs[mux]; ok {
+			continue
+		}
+		c.pairs[mux] = &pairCounters{stats: PairStats{
+			Pair:      pair,
+			Name:      fmt.Sprintf("%s/%s", pair.Pos, pair.Neg),
+			Histogram: newIntervalHistogram(),
+		}}
+		c.order = append(c.order, mux)
+	}
+	return c
+}
+
+// start records the start of the scan and the adc totals it is measured from.
+func (c *scanCounters) start(adc *ADS1256) {
+	c.mu.Lock()
+	c.stats.Start = time.Now()
+	c.lastDRDY = time.Duration(adc.drdyTime.Load())
+	c.lastSPI = time.Duration(adc.spiTime.Load())
+	c.mu.Unlock()
+}
+
+// sample records a result of pair that became ready at ready. The DRDY and SPI time spent
+// since the previous result is attributed to it. The caller must hold adc.mu.
+func (c *scanCounters) sample(adc *ADS1256, pair ChannelPair, ready time.Time, cycle uint64) {
+	now := time.Now()
+	drdy := time.Duration(adc.drdyTime.Load())
+	spi := time.Duration(adc.spiTime.Load())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.Samples++
+	c.stats.DRDYWait.add(drdy - c.lastDRDY)
+	c.stats.SPI.add(spi - c.lastSPI)
+	c.lastDRDY, c.lastSPI = drdy, spi
+
+	p, ok := c.pairs[Mux{Pos: pair.Pos, Neg: pair.Neg}.Byte()]
+	if !ok {
+		return
+	}
+	p.stats.Samples++
+	p.stats.Latency.add(now.Sub(ready))
+	p.lastCycle = cycle
+	if p.first.IsZero() {
+		p.first = ready
+	}
+	if !p.last.IsZero() {
+		interval := ready.Sub(p.last)
+		p.stats.Interval.add(interval)
+		p.stats.Histogram.add(interval)
+		x := interval.Seconds()
+		delta := x - p.mean
+		p.mean += delta / float64(p.stats.Interval.Count)
+		p.m2 += delta * (x - p.mean)
+	}
+	p.last = ready
+}
+
+// endPass counts the pass cycle as completed if every pair produced a result in it. Otherwise,
+// unless the pass was interrupted by a pause or stop, the missing pairs count as dropped.
+func (c *scanCounters) endPass(cycle uint64, interrupted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	complete := true
+	for _, p := range c.pairs {
+		if p.lastCycle != cycle {
+			complete = false
+			if !interrupted {
+				p.stats.Dropped++
+			}
+		}
+	}
+	if complete {
+		c.stats.Cycles++
+	}
+}
+
+// resume forgets the time of the last result of every pair, so that a pause does not show up
+// as an interval.
+func (c *scanCounters) resume() {
+	c.mu.Lock()
+	for _, p := range c.pairs {
+		p.last = time.Time{}
+	}
+	c.mu.Unlock()
+}
+
+func (c *scanCounters) miss(m DeadlineMiss) {
+	c.mu.Lock()
+	c.stats.Missed++
+	c.stats.Skipped += m.Skipped
+	c.mu.Unlock()
+}
+
+func (c *scanCounters) addErr(err error) {
+	c.mu.Lock()
+	c.stats.Errors.add(err)
+	c.mu.Unlock()
+}
+
+func (c *scanCounters) snapshot() ScanStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stats
+	if !s.Start.IsZero() {
+		s.Elapsed = time.Since(s.Start)
+	}
+	if s.Elapsed > 0 {
+		s.SPS = float64(s.Samples) / s.Elapsed.Seconds()
+	}
+	s.Pairs = make([]PairStats, 0, len(c.order))
+	for _, mux := range c.order {
+		p := c.pairs[mux]
+		ps := p.stats
+		ps.Histogram = ps.Histogram.clone()
+		if span := p.last.Sub(p.first); ps.Samples > 1 && span > 0 {
+			ps.SPS = float64(ps.Samples-1) / span.Seconds()
+		}
+		if n := ps.Interval.Count; n > 1 {
+			ps.Jitter = time.Duration(math.Sqrt(p.m2/float64(n-1)) * float64(time.Second))
+		}
+		s.Pairs = append(s.Pairs, ps)
+	}
+	return s
+}
+
+// Stats returns a snapshot of the scan's performance counters. It may be called at any time,
+// also after the scan has stopped.
+func (cs *ChannelScan) Stats() ScanStats {
+	return cs.stats.snapshot()
+}
+
+// timeDRDY adds the time spent in a DRDY wait that started at start to the adc total.
+func (adc *ADS1256) timeDRDY(start time.Time) {
+	adc.drdyTime.Add(int64(time.Since(start)))
+}
+
+// timeSPI adds the time spent in a transfer that started at start to the adc total.
+func (adc *ADS1256) timeSPI(start time.Time) {
+	adc.spiTime.Add(int64(time.Since(start)))
+}
@@ -0,0 +1,231 @@
+package ads1256
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrStreamStopped is returned by [Stream.Read] once the stream has stopped and its buffer is empty.
+var ErrStreamStopped = classError(ErrConfig, "stream stopped")
+
+// Stream is a true single-channel continuous acquisition. The multiplexer is set once, the
+// device is put in RDATAC mode, and every DRDY clocks out three bytes with no further commands,
+// so the ADS1256 runs at its full configured data rate.
+//
+// Samples are stored in a preallocated ring buffer. When the consumer falls behind, the oldest
+// samples are overwritten and counted as overruns.
+//
+// A running stream holds the ADC lock, so every other ADS1256 method blocks until it is stopped.
+type Stream struct {
+	pair ChannelPair
+
+	mu    sync.Mutex
+	ring  []int32
+	head  int // index of the oldest buffered sample
+	count int // number of buffered samples
+
+	notify chan struct{}
+	done   chan struct{}
+	cancel context.CancelFunc
+	err    error
+
+	samples  atomic.Uint64
+	overruns atomic.Uint64
+}
+
+// StartStream starts streaming conversions of a single channel pair into a ring buffer holding
+// bufSize samples. The stream runs until ctx is cancelled or [Stream.Stop] is called. It fails
+// with [ErrInvalidSetting] if [Config.SCLKHz] is too slow to read every result at the data rate.
+func (adc *ADS1256) StartStream(ctx context.Context, pair ChannelPair, bufSize int) (*Stream, error) {
+	if bufSize < 1 {
+		return nil, fmt.Errorf("%w: stream buffer size %d", ErrInvalidSetting, bufSize)
+	}
+
+	adc.mu.Lock()
+
+	drate := adc.regLW[REG_DRATE]
+	if pair.Settings != nil {
+		drate = pair.Settings.DataRate
+	}
+	if err := adc.timing.checkReadout(drate); err != nil {
+		adc.mu.Unlock()
+		return nil, err
+	}
+
+	if err := adc.startContinuous(pair); err != nil {
+		adc.mu.Unlock()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Stream{
+		pair:   pair,
+		ring:   make([]int32, bufSize),
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+
+	go func() {
+		err := adc.stream(ctx, s)
+		err = errors.Join(err, adc.stopContinuous())
+		adc.mu.Unlock()
+
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+		close(s.done)
+	}()
+
+	return s, nil
+}
+
+// startContinuous selects pair, restarts the conversion and enters RDATAC mode.
+func (adc *ADS1256) startContinuous(pair ChannelPair) error {
+	if err := adc.startConversion(pair); err != nil {
+		return err
+	}
+	if err := adc.sendCommand(CMD_RDATAC); err != nil {
+		return err
+	}
+
+	// t6: RDATAC to first data read
+	delay(adc.timing.T6())
+	return nil
+}
+
+// stopContinuous sends SDATAC and leaves CS high.
+func (adc *ADS1256) stopContinuous() error {
+	if err := adc.setCSLow(); err != nil {
+		return err
+	}
+	return errors.Join(adc.exitContinuous(), adc.setCSHigh())
+}
+
+func (adc *ADS1256) stream(ctx context.Context, s *Stream) error {
+	if err := adc.setCSLow(); err != nil {
+		return err
+	}
+
+	raw := get3Bytes()
+	defer put3Bytes(raw)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		if err := adc.waitDRDY(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if _, err := adc.Read(raw); err != nil {
+			return opError(OP_READ_DATA, CMD_RDATAC, err)
+		}
+		s.push(adc.decode(raw))
+	}
+}
+
+func (s *Stream) push(code int32) {
+	s.mu.Lock()
+	tail := (s.head + s.count) % len(s.ring)
+	s.ring[tail] = code
+	if s.count == len(s.ring) {
+		s.head = (s.head + 1) % len(s.ring)
+		s.overruns.Add(1)
+	} else {
+		s.count++
+	}
+	s.mu.Unlock()
+
+	s.samples.Add(1)
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Pair returns the channel pair being streamed.
+func (s *Stream) Pair() ChannelPair {
+	return s.pair
+}
+
+// Read moves up to len(dst) of the oldest buffered samples into dst. It blocks until at least one
+// sample is available, ctx is done, or the stream stops. Once the stream has stopped and the buffer
+// is drained it returns [ErrStreamStopped].
+func (s *Stream) Read(ctx context.Context, dst []int32) (int, error) {
+	for {
+		if n := s.drain(dst); n > 0 || len(dst) == 0 {
+			return n, nil
+		}
+		select {
+		case <-s.notify:
+		case <-s.done:
+			if n := s.drain(dst); n > 0 {
+				return n, nil
+			}
+			return 0, ErrStreamStopped
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+func (s *Stream) drain(dst []int32) int {
+	s.mu.Lock()
+	n := min(len(dst), s.count)
+	for i := 0; i < n; i++ {
+		dst[i] = s.ring[(s.head+i)%len(s.ring)]
+	}
+	s.head = (s.head + n) % len(s.ring)
+	s.count -= n
+	s.mu.Unlock()
+	return n
+}
+
+// Buffered returns the number of samples waiting to be read.
+func (s *Stream) Buffered() int {
+	s.mu.Lock()
+	n := s.count
+	s.mu.Unlock()
+	return n
+}
+
+// Samples returns the total number of samples acquired, including overwritten ones.
+func (s *Stream) Samples() uint64 {
+	return s.samples.Load()
+}
+
+// Overruns returns the number of samples that were overwritten before they could be read.
+func (s *Stream) Overruns() uint64 {
+	return s.overruns.Load()
+}
+
+// Done is closed once the stream has stopped and the device has left RDATAC mode.
+func (s *Stream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the error that stopped the stream, if any. It is only meaningful after [Stream.Done] is closed.
+func (s *Stream) Err() error {
+	s.mu.Lock()
+	err := s.err
+	s.mu.Unlock()
+	return err
+}
+
+// Stop stops the stream, waits for the device to leave RDATAC mode, and returns any error that
+// occurred while streaming. Buffered samples remain readable.
+func (s *Stream) Stop() error {
+	s.cancel()
+	<-s.done
+	return s.Err()
+}
@@ -0,0 +1,144 @@
+package ads1256
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultClockHz is the nominal master clock (fCLKIN) of the ADS1256, a 7.68 MHz crystal.
+const DefaultClockHz = 7.68e6
+
+// DefaultSCLKHz is the SPI clock assumed when [Config.SCLKHz] is not set.
+const DefaultSCLKHz = 1.5e6
+
+// settlingTimes maps DRATE register codes to the settling time after SYNC or a MUX change,
+// i.e. the time from the WAKEUP command to DRDY, for fCLKIN = 7.68 MHz.
+// Source: Table 13 of the datasheet.
+var settlingTimes = map[byte]time.Duration{
+	DRATE_DR_2p5_SPS:   400180 * time.Microsecond,
+	DRATE_DR_5_SPS:     200180 * time.Microsecond,
+	DRATE_DR_10_SPS:    100180 * time.Microsecond,
+	DRATE_DR_15_SPS:    66840 * time.Microsecond,
+	DRATE_DR_25_SPS:    40180 * time.Microsecond,
+	DRATE_DR_30_SPS:    33510 * time.Microsecond,
+	DRATE_DR_50_SPS:    20180 * time.Microsecond,
+	DRATE_DR_60_SPS:    16840 * time.Microsecond,
+	DRATE_DR_100_SPS:   10180 * time.Microsecond,
+	DRATE_DR_500_SPS:   2180 * time.Microsecond,
+	DRATE_DR_1000_SPS:  1180 * time.Microsecond,
+	DRATE_DR_2000_SPS:  680 * time.Microsecond,
+	DRATE_DR_3750_SPS:  440 * time.Microsecond,
+	DRATE_DR_7500_SPS:  310 * time.Microsecond,
+	DRATE_DR_15000_SPS: 250 * time.Microsecond,
+	DRATE_DR_30000_SPS: 210 * time.Microsecond,
+}
+
+// Timing derives the delays required by the datasheet timing characteristics from the master
+// clock and the SPI clock. Every delay is in units of τCLKIN = 1/fCLKIN and is scaled by
+// 1 + Margin.
+type Timing struct {
+	ClockHz float64 // fCLKIN
+	SCLKHz  float64 // SPI clock
+	Margin  float64 // Safety margin added to every delay, as a fraction (0.25 = +25%)
+}
+
+// Timing returns the timing model in use, as set up by Initialize.
+func (adc *ADS1256) Timing() Timing {
+	adc.mu.RLock()
+	t := adc.timing
+	adc.mu.RUnlock()
+	return t
+}
+
+// timing returns the timing model described by the configuration.
+func (cfg Config) timing() Timing {
+	t := Timing{ClockHz: cfg.ClockHz, SCLKHz: cfg.SCLKHz, Margin: cfg.TimingMargin}
+	if t.ClockHz <= 0 {
+		t.ClockHz = DefaultClockHz
+	}
+	if t.SCLKHz <= 0 {
+		t.SCLKHz = DefaultSCLKHz
+	}
+	return t
+}
+
+// Validate checks that the clocks are usable: SCLK may not be faster than fCLKIN/4 (t1 ≥ 4τCLKIN).
+func (t Timing) Validate() error {
+	if t.ClockHz <= 0 || t.SCLKHz <= 0 || t.Margin < 0 {
+		return fmt.Errorf("%w: timing fCLKIN %g Hz, SCLK %g Hz, margin %g", ErrInvalidSetting, t.ClockHz, t.SCLKHz, t.Margin)
+	}
+	if t.SCLKHz > t.ClockHz/4 {
+		return fmt.Errorf("%w: SCLK %g Hz is faster than fCLKIN/4 (%g Hz)", ErrInvalidSetting, t.SCLKHz, t.ClockHz/4)
+	}
+	return nil
+}
+
+// clocks returns the duration of n master clock periods, including the margin.
+func (t Timing) clocks(n float64) time.Duration {
+	return time.Duration(n / t.ClockHz * (1 + t.Margin) * float64(time.Second))
+}
+
+// T6 is the delay from the last SCLK edge of an RDATA, RDATAC or RREG command to the first
+// SCLK edge reading data (t6 = 50 τCLKIN).
+func (t Timing) T6() time.Duration {
+	return t.clocks(50)
+}
+
+// T11 is the delay from the last SCLK edge of cmd to the first SCLK edge of the next command
+// (t11 = 24 τCLKIN after RDATAC, RESET and SYNC, 4 τCLKIN after anything else).
+func (t Timing) T11(cmd byte) time.Duration {
+	switch cmd {
+	case CMD_RDATAC, CMD_RESET, CMD_SYNC:
+		return t.clocks(24)
+	default:
+		return t.clocks(4)
+	}
+}
+
+// Transfer is the time it takes to clock n bytes over SPI.
+func (t Timing) Transfer(n int) time.Duration {
+	return time.Duration(float64(8*n) / t.SCLKHz * (1 + t.Margin) * float64(time.Second))
+}
+
+// checkReadout checks that a conversion result can be clocked out within one conversion period
+// at a data rate. In RDATAC mode a result that is still being read when the next DRDY arrives is
+// lost, so a slower SPI clock cannot keep up with the rate at all.
+func (t Timing) checkReadout(drate byte) error {
+	info, err := DataRateAt(drate, t.ClockHz)
+	if err != nil {
+		return err
+	}
+	period := info.Period()
+	if readout := t.Transfer(3); readout >= period {
+		return fmt.Errorf("%w: reading a result takes %s at SCLK %g Hz, the conversion period at %g SPS is %s",
+			ErrInvalidSetting, readout, t.SCLKHz, info.SPS, period)
+	}
+	return nil
+}
+
+// Settling is the time from WAKEUP after SYNC or a MUX change until the first settled
+// conversion is ready at a data rate. Unknown codes are treated as the slowest rate.
+func (t Timing) Settling(drate byte) time.Duration {
+	d, ok := settlingTimes[drate]
+	if !ok {
+		d = settlingTimes[DRATE_DR_2p5_SPS]
+	}
+	return time.Duration(float64(d) * DefaultClockHz / t.ClockHz * (1 + t.Margin))
+}
+
+// spinThreshold is the longest delay that is busy-waited rather than slept; time.Sleep
+// overshoots short delays by tens of microseconds.
+const spinThreshold = 100 * time.Microsecond
+
+// delay waits for d, spinning for short delays.
+func delay(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	if d >= spinThreshold {
+		time.Sleep(d)
+		return
+	}
+	for start := time.Now(); time.Since(start) < d; {
+	}
+}
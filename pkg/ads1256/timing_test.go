@@ -0,0 +1,121 @@
+package ads1256
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTiming(t *testing.T) {
+	tm := Timing{ClockHz: DefaultClockHz, SCLKHz: DefaultSCLKHz}
+
+	t.Run("Delays", func(t *testing.T) {
+		tests := []struct {
+			name string
+			got  time.Duration
+			want time.Duration
+		}{
+			{"T6", tm.T6(), 6510 * time.Nanosecond},
+			{"T11/SYNC", tm.T11(CMD_SYNC), 3125 * time.Nanosecond},
+			{"T11/RDATAC", tm.T11(CMD_RDATAC), 3125 * time.Nanosecond},
+			{"T11/WREG", tm.T11(CMD_WREG), 520 * time.Nanosecond},
+			{"Transfer", tm.Transfer(3), 16 * time.Microsecond},
+			{"Settling", tm.Settling(DRATE_DR_1000_SPS), 1180 * time.Microsecond},
+			{"SettlingUnknown", tm.Settling(0x00), 400180 * time.Microsecond},
+		}
+		for _, tt := range tests {
+			if tt.got != tt.want {
+				t.Errorf("%s: expected %s, got %s", tt.name, tt.want, tt.got)
+			}
+		}
+	})
+
+	t.Run("Margin", func(t *testing.T) {
+		m := tm
+		m.Margin = 0.5
+		if got, want := m.T6(), tm.T6()*3/2; got < want-time.Nanosecond || got > want+time.Nanosecond {
+			t.Errorf("expected %s, got %s", want, got)
+		}
+	})
+
+	t.Run("SlowClock", func(t *testing.T) {
+		slow := tm
+		slow.ClockHz = DefaultClockHz / 2
+		if got := slow.Settling(DRATE_DR_1000_SPS); got != 2360*time.Microsecond {
+			t.Errorf("expected %s, got %s", 2360*time.Microsecond, got)
+		}
+		if got := slow.T6(); got != 13020*time.Nanosecond {
+			t.Errorf("expected %s, got %s", 13020*time.Nanosecond, got)
+		}
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		if err := tm.Validate(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		fast := tm
+		fast.SCLKHz = 2e6
+		if err := fast.Validate(); err == nil {
+			t.Error("expected an error for SCLK > fCLKIN/4")
+		}
+		if err := (Timing{ClockHz: DefaultClockHz, SCLKHz: 1e6, Margin: -1}).Validate(); err == nil {
+			t.Error("expected an error for a negative margin")
+		}
+	})
+
+	t.Run("Readout", func(t *testing.T) {
+		// 3 bytes take 16µs at 1.5 MHz, within the 33µs of a conversion at 30 kSPS
+		if err := tm.checkReadout(DRATE_DR_30000_SPS); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		slow := tm
+		slow.SCLKHz = 500e3
+		if err := slow.checkReadout(DRATE_DR_30000_SPS); !errors.Is(err, ErrInvalidSetting) {
+			t.Errorf("expected ErrInvalidSetting at 30 kSPS, got %v", err)
+		}
+		if err := slow.checkReadout(DRATE_DR_15000_SPS); err != nil {
+			t.Errorf("unexpected error at 15 kSPS: %v", err)
+		}
+	})
+}
+
+func TestDataRateAt(t *testing.T) {
+	info, err := DataRateAt(DRATE_DR_1000_SPS, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.SPS != 1000 || info.Notch != 1000 || info.Settling != 1180*time.Microsecond || info.Period() != time.Millisecond {
+		t.Errorf("unexpected info at 7.68 MHz: %s", info)
+	}
+
+	info, err = DataRateAt(DRATE_DR_60_SPS, 6e6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := 60 * 6e6 / 7.68e6; info.SPS != want {
+		t.Errorf("expected %g SPS at 6 MHz, got %g", want, info.SPS)
+	}
+
+	if _, err = DataRateAt(0x00, 0); err == nil {
+		t.Error("expected an error for an invalid code")
+	}
+
+	info, _ = DataRateAt(DRATE_DR_10_SPS, 0)
+	if !info.Rejects(50) || !info.Rejects(60) || info.Rejects(55) || info.Rejects(5) {
+		t.Errorf("unexpected notches for %s", info)
+	}
+
+	info, err = DataRateFor(55, 0)
+	if err != nil || info.Code != DRATE_DR_50_SPS && info.Code != DRATE_DR_60_SPS {
+		t.Errorf("expected 50 or 60 SPS for 55 SPS, got %s (%v)", info, err)
+	}
+	info, err = DataRateFor(1000, 3.84e6)
+	if err != nil || info.Code != DRATE_DR_2000_SPS {
+		t.Errorf("expected the 2000 SPS code at 3.84 MHz, got %s (%v)", info, err)
+	}
+
+	table, err := DataRateTable(0)
+	if err != nil || len(table) != 16 || table[0].Code != DRATE_DR_2p5_SPS || table[15].Code != DRATE_DR_30000_SPS {
+		t.Errorf("unexpected table: %v (%v)", table, err)
+	}
+}
@@ -1,5 +1,7 @@
 package ads1256
 
+import "math/bits"
+
 // Convert24To32 interprets a 3-byte, 24-bit signed value
 // in two's complement form, MSB first, as a 32-bit int.
 func Convert24To32(data []byte) int32 {
@@ -16,8 +18,27 @@ func Convert24To32(data []byte) int32 {
 	return int32(u32)
 }
 
+// Decode24 interprets a 3-byte conversion result as sent by the device. With lsbFirst
+// (STATUS ORDER set) the bits within each byte arrive least significant first; the bytes
+// themselves are always sent most significant byte first.
+func Decode24(data []byte, lsbFirst bool) int32 {
+	if !lsbFirst {
+		return Convert24To32(data)
+	}
+	return Convert24To32([]byte{bits.Reverse8(data[0]), bits.Reverse8(data[1]), bits.Reverse8(data[2])})
+}
+
+// decode interprets a conversion result in the bit order currently programmed into STATUS.
+func (adc *ADS1256) decode(data []byte) int32 {
+	return Decode24(data, adc.regLW[REG_STATUS]&STATUS_ORDER != 0)
+}
+
 // ConvertADCtoVolts converts the signed 24-bit code to a voltage.
 // full-scale range = ±2 * Vref / PGA. For a code of 0x7FFFFF => +FS.
+//
+// Deprecated: pga here is the gain itself, not the ADCON_PGA_* code the driver stores,
+// and the scaling is off by one code. Use [CodeToVolts] or [ADS1256.Volts].
+//
 // Credit: https://github.com/vgasparyan/ads1256-rs/blob/eee60a5dc138dd16aa0443ea161692c1246197d4/src/lib.rs#L314-L316
 func (adc *ADS1256) ConvertADCtoVolts(code int32, vRef float64, pga int) float64 {
 	// The ADS1256 is a 24-bit device with a max positive code of 0x7FFFFF
@@ -79,3 +79,57 @@ func TestConvertADCtoVolts(t *testing.T) {
 		}
 	})
 }
+
+func TestCodeToVolts(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        int32
+		pga         byte
+		volts       float64
+		over, under bool
+	}{
+		{"Zero", 0, ADCON_PGA_1, 0, false, false},
+		{"QuarterScale", 1 << 21, ADCON_PGA_1, 1.25, false, false},
+		{"PGA16", 1 << 21, ADCON_PGA_16, 1.25 / 16, false, false},
+		{"PGA64", -(1 << 22), ADCON_PGA_64, -2.5 / 64, false, false},
+		{"Overrange", CodeMax, ADCON_PGA_1, 5 - 5.0/(1<<23), true, false},
+		{"Underrange", CodeMin, ADCON_PGA_2, -2.5, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := CodeToVolts(tt.code, 2.5, tt.pga)
+			if v.Volts != tt.volts {
+				t.Errorf("expected %gV, got %gV", tt.volts, v.Volts)
+			}
+			if v.Overrange != tt.over || v.Underrange != tt.under {
+				t.Errorf("expected over=%v under=%v, got %+v", tt.over, tt.under, v)
+			}
+			if v.InRange() != (!tt.over && !tt.under) {
+				t.Errorf("InRange mismatch for %+v", v)
+			}
+		})
+	}
+}
+
+func TestDecode24(t *testing.T) {
+	tests := []struct {
+		name string
+		msb  []byte
+		lsb  []byte
+		want int32
+	}{
+		{"Positive", []byte{0x12, 0x34, 0x56}, []byte{0x48, 0x2C, 0x6A}, 0x123456},
+		{"Negative", []byte{0x80, 0x00, 0x01}, []byte{0x01, 0x00, 0x80}, -8388607},
+		{"MaxPositive", []byte{0x7F, 0xFF, 0xFF}, []byte{0xFE, 0xFF, 0xFF}, 8388607},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Decode24(tt.msb, false); got != tt.want {
+				t.Errorf("MSB first: expected %d, got %d", tt.want, got)
+			}
+			if got := Decode24(tt.lsb, true); got != tt.want {
+				t.Errorf("LSB first: expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
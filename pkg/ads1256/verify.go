@@ -0,0 +1,95 @@
+package ads1256
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ChipID is the factory programmed value of the STATUS ID bits (7:4) on an ADS1256.
+const ChipID = 0x03
+
+// DefaultInitAttempts is how often Initialize tries to configure the device when
+// [Config.InitAttempts] is not set.
+const DefaultInitAttempts = 3
+
+var (
+	// ErrChipID is matched by a [ChipIDError].
+	ErrChipID = classError(ErrDevice, "unexpected chip ID")
+	// ErrRegisterMismatch is matched by a [RegisterMismatchError].
+	ErrRegisterMismatch = classError(ErrDevice, "register read-back mismatch")
+)
+
+// ChipIDError is returned by Initialize when the STATUS ID bits do not identify an ADS1256.
+// A value of 0x0 or 0xF usually means MISO is stuck or the SPI mode is wrong.
+type ChipIDError struct {
+	ID byte // ID bits as read
+}
+
+func (e *ChipIDError) Error() string {
+	return fmt.Sprintf("unexpected chip ID 0x%X (expected 0x%X)", e.ID, ChipID)
+}
+
+func (e *ChipIDError) Unwrap() error {
+	return ErrChipID
+}
+
+// RegisterMismatchError is returned by Initialize when a register reads back differently
+// from what was written. Only bits that can be written are compared.
+type RegisterMismatchError struct {
+	Register Register
+	Wrote    byte
+	Read     byte
+	Mask     byte // Bits that were compared
+}
+
+func (e *RegisterMismatchError) Error() string {
+	return fmt.Sprintf("register %s: wrote 0x%02X, read back 0x%02X (mask 0x%02X)", e.Register, e.Wrote, e.Read, e.Mask)
+}
+
+func (e *RegisterMismatchError) Unwrap() error {
+	return ErrRegisterMismatch
+}
+
+// writableMask returns the bits of reg that read back as written.
+func writableMask(reg Register, wrote byte) byte {
+	switch reg {
+	case REG_STATUS:
+		// ID and DRDY are read-only
+		return STATUS_ORDER | STATUS_ACAL | STATUS_BUFEN
+	case REG_ADCON:
+		// bit 7 is reserved and reads 0
+		return 0x7F
+	case REG_IO:
+		// DIO bits of inputs read the pin level, not the written value
+		return 0xF0 | (^wrote>>4)&0x0F
+	default:
+		return 0xFF
+	}
+}
+
+// verifyRegisters compares the registers written starting at start with the last read-back.
+// The caller must have read them back since they were written.
+func (adc *ADS1256) verifyRegisters(start Register, values []byte) error {
+	var errs []error
+	for i, wrote := range values {
+		reg := start + Register(i)
+		mask := writableMask(reg, wrote)
+		if read := adc.regLR[reg]; read&mask != wrote&mask {
+			errs = append(errs, &RegisterMismatchError{Register: reg, Wrote: wrote, Read: read, Mask: mask})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkChipID checks the ID bits of the last read STATUS register.
+func (adc *ADS1256) checkChipID() error {
+	if id := ParseStatus(adc.regLR[REG_STATUS]).ID; id != ChipID {
+		return &ChipIDError{ID: id}
+	}
+	return nil
+}
+
+// isVerifyError reports whether err is a failed verification, which is worth retrying.
+func isVerifyError(err error) bool {
+	return errors.Is(err, ErrChipID) || errors.Is(err, ErrRegisterMismatch)
+}
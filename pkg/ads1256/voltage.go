@@ -0,0 +1,114 @@
+package ads1256
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultVRef is the reference voltage (VREFP - VREFN) assumed when [Config.VRef] is not set.
+const DefaultVRef = 2.5
+
+// Output code limits. A conversion clipped to one of these is out of range.
+const (
+	CodeMax int32 = 1<<23 - 1  // 0x7FFFFF, positive full scale
+	CodeMin int32 = -(1 << 23) // 0x800000, negative full scale
+)
+
+// Voltage is a conversion result expressed as a differential input voltage.
+type Voltage struct {
+	Volts      float64
+	Overrange  bool // The code was clipped at +full scale; the real input is at least Volts
+	Underrange bool // The code was clipped at -full scale; the real input is at most Volts
+}
+
+// InRange reports whether the conversion was within the input range.
+func (v Voltage) InRange() bool {
+	return !v.Overrange && !v.Underrange
+}
+
+func (v Voltage) String() string {
+	switch {
+	case v.Overrange:
+		return fmt.Sprintf(">=%.9fV (overrange)", v.Volts)
+	case v.Underrange:
+		return fmt.Sprintf("<=%.9fV (underrange)", v.Volts)
+	default:
+		return fmt.Sprintf("%.9fV", v.Volts)
+	}
+}
+
+// LSBVolts returns the weight of one code for the given reference voltage and PGA setting
+// (an ADCON_PGA_* code, not the gain itself). The input range is ±2·VREF/gain over 2^23 codes.
+func LSBVolts(vRef float64, pga byte) float64 {
+	return 2 * vRef / float64(ADCON{PGA: pga}.Gain()) / (1 << 23)
+}
+
+// CodeToVolts converts a signed 24-bit conversion result to a [Voltage], given the reference
+// voltage and the PGA setting (an ADCON_PGA_* code) that were active for the conversion.
+func CodeToVolts(code int32, vRef float64, pga byte) Voltage {
+	return Voltage{
+		Volts:      float64(code) * LSBVolts(vRef, pga),
+		Overrange:  code >= CodeMax,
+		Underrange: code <= CodeMin,
+	}
+}
+
+// VRef returns the reference voltage used for conversions.
+func (adc *ADS1256) VRef() float64 {
+	adc.mu.RLock()
+	v := adc.vRef()
+	adc.mu.RUnlock()
+	return v
+}
+
+// SetVRef changes the reference voltage used for conversions, e.g. after measuring it.
+func (adc *ADS1256) SetVRef(vRef float64) {
+	adc.mu.Lock()
+	adc.cfg.VRef = vRef
+	adc.mu.Unlock()
+}
+
+func (adc *ADS1256) vRef() float64 {
+	if adc.cfg.VRef <= 0 {
+		return DefaultVRef
+	}
+	return adc.cfg.VRef
+}
+
+// PGA returns the PGA setting (an ADCON_PGA_* code) currently programmed into the device.
+func (adc *ADS1256) PGA() byte {
+	adc.mu.RLock()
+	pga := ParseADCON(adc.regLW[REG_ADCON]).PGA
+	adc.mu.RUnlock()
+	return pga
+}
+
+// Volts converts a code using the reference voltage and the PGA setting currently programmed
+// into the device. Use it for results of [ADS1256.ReadChannel] and [ADS1256.SingleConversion].
+func (adc *ADS1256) Volts(code int32) Voltage {
+	adc.mu.RLock()
+	v := CodeToVolts(code, adc.vRef(), ParseADCON(adc.regLW[REG_ADCON]).PGA)
+	adc.mu.RUnlock()
+	return v
+}
+
+// PairVolts converts a code delivered to a scan callback, using the PGA the pair was acquired
+// with (see [ChannelPair.Settings]).
+func (adc *ADS1256) PairVolts(pair ChannelPair, code int32) Voltage {
+	if pair.Settings == nil {
+		return adc.Volts(code)
+	}
+	return CodeToVolts(code, adc.VRef(), pair.Settings.PGA)
+}
+
+// ReadChannelVolts is [ADS1256.ReadChannel], converted to volts with the PGA that was active
+// for the conversion.
+func (adc *ADS1256) ReadChannelVolts(ainP, ainN Channel) (Voltage, error) {
+	adc.mu.Lock()
+	defer adc.mu.Unlock()
+	code, err := adc.readChannel(context.Background(), ainP, ainN)
+	if err != nil {
+		return Voltage{}, err
+	}
+	return CodeToVolts(code, adc.vRef(), ParseADCON(adc.regLW[REG_ADCON]).PGA), nil
+}
@@ -0,0 +1,632 @@
+// Package ads1256sim provides an in-process emulation of a TI ADS1256 that
+// implements [ads1256.SerialInterface].
+//
+// The emulator decodes the raw byte stream produced by the ads1256 driver the
+// same way the chip's serial interface does: commands, WREG/RREG transfers
+// against the register file, RDATA/RDATAC/SDATAC read modes, SYNC/WAKEUP/STANDBY
+// and RESET. Conversions are event driven rather than clocked: every call to
+// WaitDRDY completes one conversion of the currently selected inputs, so tests
+// are deterministic and never sleep for a data rate period.
+//
+// Source: https://www.ti.com/lit/ds/symlink/ads1256.pdf
+package ads1256sim
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/bits"
+	"sync"
+
+	"github.com/yunginnanet/ftdi-ads1256/pkg/ads1256"
+)
+
+// ChipID is the factory programmed value of the STATUS ID bits (7:4) on an ADS1256.
+const ChipID = 0x03
+
+// NominalFSC is the full-scale calibration value at which the emulated gain is exactly 1.
+const NominalFSC = 0x400000
+
+// DefaultVRef is the reference voltage used by a new [Device].
+const DefaultVRef = 2.5
+
+// Register values after power-up or RESET, from the register map in the datasheet.
+// The ID bits and DRDY bit of STATUS are filled in when the register is read.
+var resetRegisters = [ads1256.NumRegisters]byte{
+	ads1256.REG_STATUS: 0x00,
+	ads1256.REG_MUX:    0x01,
+	ads1256.REG_ADCON:  0x20,
+	ads1256.REG_DRATE:  0xF0,
+	ads1256.REG_IO:     0xE0,
+	ads1256.REG_FSC0:   NominalFSC & 0xFF,
+	ads1256.REG_FSC1:   NominalFSC >> 8 & 0xFF,
+	ads1256.REG_FSC2:   NominalFSC >> 16 & 0xFF,
+}
+
+var (
+	// ErrClosed is returned by every method once [Device.Close] has been called.
+	ErrClosed = errors.New("ads1256sim: device closed")
+
+	// ErrNoConversion is returned by [Device.WaitDRDY] when the emulated chip is in a state where
+	// DRDY would never go low (standby, SYNC without WAKEUP, or powered down) and the context has
+	// no deadline. Real hardware would simply hang here.
+	ErrNoConversion = errors.New("ads1256sim: DRDY will never assert in the current state")
+)
+
+type parseState int
+
+const (
+	stateCommand parseState = iota
+	stateRREGCount
+	stateWREGCount
+	stateWREGData
+)
+
+// Device is an emulated ADS1256. The zero value is not usable; create one with [New].
+type Device struct {
+	mu sync.Mutex
+
+	regs [ads1256.NumRegisters]byte
+
+	inputs     [ads1256.CH_AINCOM + 1]float64
+	inputFuncs [ads1256.CH_AINCOM + 1]func() float64
+	vRef       float64
+
+	// sensor-detect model: open inputs and the source resistance seen by the current source
+	open       [ads1256.CH_AINCOM + 1]bool
+	resistance [ads1256.CH_AINCOM + 1]float64
+
+	// offsetError and gainError model the chip's internal errors that self calibration removes.
+	offsetError int32
+	gainError   float64
+
+	dioIn byte // levels driven onto D0..D3 from outside the chip
+
+	// serial interface state
+	selected bool
+	state    parseState
+	addr     byte
+	remain   int
+	out      []byte
+
+	// conversion state
+	continuous  bool
+	standby     bool
+	synced      bool
+	poweredDown bool
+	ready       bool
+	data        int32
+	conversions uint64
+
+	closed   bool
+	commands []byte
+}
+
+// New returns an emulated ADS1256 in its power-up state with all inputs at 0 V.
+func New() *Device {
+	d := &Device{vRef: DefaultVRef}
+	d.reset()
+	return d
+}
+
+func (d *Device) reset() {
+	d.regs = resetRegisters
+	d.state = stateCommand
+	d.out = d.out[:0]
+	d.continuous = false
+	d.standby = false
+	d.synced = false
+	d.ready = false
+	d.data = 0
+}
+
+// SetInput sets the voltage present on the given analog input, relative to analog ground.
+func (d *Device) SetInput(ch ads1256.Channel, volts float64) {
+	d.mu.Lock()
+	d.inputs[ch] = volts
+	d.inputFuncs[ch] = nil
+	d.mu.Unlock()
+}
+
+// SetInputFunc installs a function that is sampled for the given analog input on every conversion.
+// Passing nil reverts the input to the last value given to [Device.SetInput].
+func (d *Device) SetInputFunc(ch ads1256.Channel, f func() float64) {
+	d.mu.Lock()
+	d.inputFuncs[ch] = f
+	d.mu.Unlock()
+}
+
+// Input returns the voltage currently present on the given analog input.
+func (d *Device) Input(ch ads1256.Channel) float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.input(ch)
+}
+
+func (d *Device) input(ch ads1256.Channel) float64 {
+	if f := d.inputFuncs[ch]; f != nil {
+		return f()
+	}
+	return d.inputs[ch]
+}
+
+// SetOpen marks an analog input as open circuit (e.g. a broken sensor wire). An open input reads
+// 0 V, but is driven to full scale while the sensor-detect current source is on.
+func (d *Device) SetOpen(ch ads1256.Channel, open bool) {
+	d.mu.Lock()
+	d.open[ch] = open
+	d.mu.Unlock()
+}
+
+// SetSourceResistance sets the resistance of the source connected to an analog input. The
+// sensor-detect current develops a voltage across it.
+func (d *Device) SetSourceResistance(ch ads1256.Channel, ohms float64) {
+	d.mu.Lock()
+	d.resistance[ch] = ohms
+	d.mu.Unlock()
+}
+
+// SetVRef sets the emulated reference voltage (VREFP - VREFN).
+func (d *Device) SetVRef(volts float64) {
+	d.mu.Lock()
+	d.vRef = volts
+	d.mu.Unlock()
+}
+
+// SetOffsetError sets the internal offset, in codes, that is added to every conversion
+// until an offset calibration removes it.
+func (d *Device) SetOffsetError(codes int32) {
+	d.mu.Lock()
+	d.offsetError = codes
+	d.mu.Unlock()
+}
+
+// SetGainError sets the internal gain error as a fraction (0.01 == +1%) that is applied to every
+// conversion until a gain calibration removes it.
+func (d *Device) SetGainError(fraction float64) {
+	d.mu.Lock()
+	d.gainError = fraction
+	d.mu.Unlock()
+}
+
+// SetDigitalInput sets the level driven onto digital pin D0..D3 from outside the chip.
+// It is only visible in REG_IO while the pin is configured as an input.
+func (d *Device) SetDigitalInput(pin int, high bool) {
+	d.mu.Lock()
+	if high {
+		d.dioIn |= 1 << pin
+	} else {
+		d.dioIn &^= 1 << pin
+	}
+	d.mu.Unlock()
+}
+
+// DigitalOutput returns the level the chip is driving onto digital pin D0..D3, and whether the
+// pin is configured as an output at all.
+func (d *Device) DigitalOutput(pin int) (high bool, output bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	io := d.regs[ads1256.REG_IO]
+	return io&(1<<pin) != 0, io&(0x10<<pin) == 0
+}
+
+// Register returns the raw contents of a register as the chip would report them over RREG.
+func (d *Device) Register(reg ads1256.Register) byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.readReg(byte(reg))
+}
+
+// SetRegister overwrites a register directly, bypassing the serial interface and write masks.
+func (d *Device) SetRegister(reg ads1256.Register, value byte) {
+	d.mu.Lock()
+	d.regs[reg] = value
+	d.mu.Unlock()
+}
+
+// Continuous reports whether the emulated chip is in RDATAC mode.
+func (d *Device) Continuous() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.continuous
+}
+
+// Standby reports whether the emulated chip is in STANDBY mode.
+func (d *Device) Standby() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.standby
+}
+
+// PoweredDown reports whether the PWDN line is being held low.
+func (d *Device) PoweredDown() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.poweredDown
+}
+
+// Conversions returns the number of conversions completed since [New].
+func (d *Device) Conversions() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.conversions
+}
+
+// Commands returns every command opcode decoded so far, in order. WREG/RREG are reported with
+// their register address in the low nibble, exactly as sent.
+func (d *Device) Commands() []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]byte(nil), d.commands...)
+}
+
+// CommandCount returns how many times the given opcode has been decoded. For WREG and RREG the
+// register address nibble is ignored.
+func (d *Device) CommandCount(cmd byte) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := 0
+	for _, c := range d.commands {
+		switch cmd & 0xF0 {
+		case ads1256.CMD_RREG, ads1256.CMD_WREG:
+			if c&0xF0 == cmd&0xF0 {
+				n++
+			}
+		default:
+			if c == cmd {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Init implements [ads1256.SerialInterface].
+func (d *Device) Init() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+// Close implements [ads1256.SerialInterface].
+func (d *Device) Close() error {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+	return nil
+}
+
+// PowerDown implements [ads1256.SerialInterface] by holding the emulated SYNC/PDWN line low.
+// Like the GPIO line it models, it keeps working after [Device.Close].
+func (d *Device) PowerDown() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.poweredDown = true
+	d.ready = false
+	return nil
+}
+
+// PowerUp implements [ads1256.SerialInterface] by releasing the emulated SYNC/PDWN line.
+func (d *Device) PowerUp() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.poweredDown = false
+	return nil
+}
+
+// SetCS implements [ads1256.SerialInterface]. Taking CS high resets the serial interface,
+// discarding any partially received command and any unread output.
+func (d *Device) SetCS(high bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return ErrClosed
+	}
+	d.selected = !high
+	if high {
+		d.state = stateCommand
+		d.out = d.out[:0]
+	}
+	return nil
+}
+
+// WaitDRDY implements [ads1256.SerialInterface]. If DRDY is already low it returns immediately,
+// otherwise it completes one conversion of the currently selected inputs.
+//
+// In a state where no conversion can complete, it behaves like real hardware and blocks until
+// ctx is done, returning ctx.Err(). If ctx can never be done, it returns [ErrNoConversion] instead.
+func (d *Device) WaitDRDY(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return ErrClosed
+	}
+	if d.ready {
+		return nil
+	}
+	if d.poweredDown || d.standby || d.synced {
+		if ctx.Done() == nil {
+			return ErrNoConversion
+		}
+		d.mu.Unlock()
+		<-ctx.Done()
+		d.mu.Lock()
+		return ctx.Err()
+	}
+	d.data = d.convert()
+	d.ready = true
+	d.conversions++
+	return nil
+}
+
+// Write implements [ads1256.SerialInterface]. Bytes are ignored while CS is high.
+func (d *Device) Write(data []byte, _ bool, _ bool) (uint, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return 0, ErrClosed
+	}
+	if !d.selected || d.poweredDown {
+		return uint(len(data)), nil
+	}
+	for _, b := range data {
+		d.clock(b)
+	}
+	return uint(len(data)), nil
+}
+
+// Read implements [ads1256.SerialInterface]. Pending RREG/RDATA output is returned first. In
+// RDATAC mode with no pending output, a ready conversion result is shifted out.
+func (d *Device) Read(count uint, _ bool, _ bool) ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil, ErrClosed
+	}
+	buf := make([]byte, count)
+	if !d.selected || d.poweredDown {
+		return buf, nil
+	}
+	if len(d.out) == 0 && d.continuous && d.ready {
+		d.out = d.appendData(d.out)
+		d.ready = false
+	}
+	n := copy(buf, d.out)
+	d.out = d.out[n:]
+	return buf, nil
+}
+
+// clock processes a single byte received on DIN.
+func (d *Device) clock(b byte) {
+	switch d.state {
+	case stateRREGCount:
+		n := int(b&0x0F) + 1
+		for i := 0; i < n && int(d.addr)+i < ads1256.NumRegisters; i++ {
+			d.out = append(d.out, d.readReg(d.addr+byte(i)))
+		}
+		d.state = stateCommand
+		return
+	case stateWREGCount:
+		d.remain = int(b&0x0F) + 1
+		d.state = stateWREGData
+		return
+	case stateWREGData:
+		if d.addr < ads1256.NumRegisters {
+			d.writeReg(d.addr, b)
+		}
+		d.addr++
+		d.remain--
+		if d.remain == 0 {
+			d.state = stateCommand
+		}
+		return
+	}
+
+	// In RDATAC mode the chip only listens for SDATAC and RESET.
+	if d.continuous && b != ads1256.CMD_SDATAC && b != ads1256.CMD_RESET {
+		return
+	}
+
+	d.commands = append(d.commands, b)
+	d.out = d.out[:0]
+
+	switch {
+	case b&0xF0 == ads1256.CMD_RREG:
+		d.addr = b & 0x0F
+		d.state = stateRREGCount
+		return
+	case b&0xF0 == ads1256.CMD_WREG:
+		d.addr = b & 0x0F
+		d.state = stateWREGCount
+		return
+	}
+
+	switch b {
+	case ads1256.CMD_WAKEUP0, ads1256.CMD_WAKEUP:
+		if d.standby || d.synced {
+			d.standby = false
+			d.synced = false
+			d.ready = false
+		}
+	case ads1256.CMD_RDATA:
+		d.out = d.appendData(d.out)
+		d.ready = false
+	case ads1256.CMD_RDATAC:
+		d.continuous = true
+	case ads1256.CMD_SDATAC:
+		d.continuous = false
+	case ads1256.CMD_SELFCAL:
+		d.selfOffsetCal()
+		d.selfGainCal()
+	case ads1256.CMD_SELFOCAL:
+		d.selfOffsetCal()
+	case ads1256.CMD_SELFGCAL:
+		d.selfGainCal()
+	case ads1256.CMD_SYSOCAL:
+		d.setOFC(d.raw())
+		d.ready = false
+	case ads1256.CMD_SYSGCAL:
+		if raw := float64(d.raw() - d.ofc()); raw > 0 {
+			d.setFSC(uint32(math.Round(NominalFSC * float64(1<<23-1) / raw)))
+		}
+		d.ready = false
+	case ads1256.CMD_SYNC:
+		d.synced = true
+		d.ready = false
+	case ads1256.CMD_STANDBY:
+		d.standby = true
+		d.ready = false
+	case ads1256.CMD_RESET:
+		d.reset()
+	}
+}
+
+func (d *Device) readReg(addr byte) byte {
+	v := d.regs[addr]
+	switch addr {
+	case ads1256.REG_STATUS:
+		v = ChipID<<4 | v&0x0E
+		if !d.ready {
+			v |= ads1256.STATUS_DRDY
+		}
+	case ads1256.REG_IO:
+		// pins configured as inputs (DIR=1) report the level driven onto them
+		dir := v >> 4
+		v = v&0xF0 | v&^dir&0x0F | d.dioIn&dir
+	}
+	return v
+}
+
+func (d *Device) writeReg(addr, value byte) {
+	switch addr {
+	case ads1256.REG_STATUS:
+		// ID and DRDY are read-only
+		value &= 0x0E
+	case ads1256.REG_ADCON:
+		value &= 0x7F
+	}
+	d.regs[addr] = value
+
+	switch addr {
+	case ads1256.REG_MUX, ads1256.REG_ADCON, ads1256.REG_DRATE, ads1256.REG_STATUS:
+		// changing the input or filter configuration restarts the conversion in progress
+		d.ready = false
+		if addr != ads1256.REG_MUX && d.regs[ads1256.REG_STATUS]&ads1256.STATUS_ACAL != 0 {
+			d.selfOffsetCal()
+			d.selfGainCal()
+		}
+	}
+}
+
+func (d *Device) selfOffsetCal() {
+	d.setOFC(d.offsetError)
+	d.ready = false
+}
+
+func (d *Device) selfGainCal() {
+	d.setFSC(uint32(math.Round(NominalFSC / (1 + d.gainError))))
+	d.ready = false
+}
+
+func (d *Device) ofc() int32 {
+	u := uint32(d.regs[ads1256.REG_OFC0]) | uint32(d.regs[ads1256.REG_OFC1])<<8 | uint32(d.regs[ads1256.REG_OFC2])<<16
+	if u&0x800000 != 0 {
+		u |= 0xFF000000
+	}
+	return int32(u)
+}
+
+func (d *Device) setOFC(v int32) {
+	d.regs[ads1256.REG_OFC0] = byte(v)
+	d.regs[ads1256.REG_OFC1] = byte(v >> 8)
+	d.regs[ads1256.REG_OFC2] = byte(v >> 16)
+}
+
+func (d *Device) fsc() uint32 {
+	return uint32(d.regs[ads1256.REG_FSC0]) | uint32(d.regs[ads1256.REG_FSC1])<<8 | uint32(d.regs[ads1256.REG_FSC2])<<16
+}
+
+func (d *Device) setFSC(v uint32) {
+	d.regs[ads1256.REG_FSC0] = byte(v)
+	d.regs[ads1256.REG_FSC1] = byte(v >> 8)
+	d.regs[ads1256.REG_FSC2] = byte(v >> 16)
+}
+
+func (d *Device) gain() float64 {
+	return float64(int(1) << min(d.regs[ads1256.REG_ADCON]&0x07, 6))
+}
+
+// sensorCurrents are the sensor-detect currents selected by ADCON SDCS1:0, in amps.
+var sensorCurrents = [4]float64{0, 0.5e-6, 2e-6, 10e-6}
+
+// diff returns the differential input voltage selected by the MUX register. The sensor-detect
+// current flows out of the positive input and back into the negative one.
+func (d *Device) diff() float64 {
+	mux := d.regs[ads1256.REG_MUX]
+	i := sensorCurrents[(d.regs[ads1256.REG_ADCON]>>3)&0x03]
+	return d.muxInput(mux>>4, i) - d.muxInput(mux&0x0F, -i)
+}
+
+func (d *Device) muxInput(sel byte, current float64) float64 {
+	if sel > byte(ads1256.CH_AINCOM) {
+		// reserved codes select AINCOM
+		sel = byte(ads1256.CH_AINCOM)
+	}
+	ch := ads1256.Channel(sel)
+	if d.open[ch] {
+		// nothing sinks the current, so the input is driven to the rail
+		switch {
+		case current > 0:
+			return math.Inf(1)
+		case current < 0:
+			return math.Inf(-1)
+		}
+		return 0
+	}
+	return d.input(ch) + current*d.resistance[ch]
+}
+
+// raw returns the modulator output for the selected inputs before calibration is applied.
+func (d *Device) raw() int32 {
+	code := d.diff() * d.gain() / (2 * d.vRef) * (1 << 23)
+	code = code*(1+d.gainError) + float64(d.offsetError)
+	return clamp24(code)
+}
+
+// convert returns the calibrated conversion result for the selected inputs.
+func (d *Device) convert() int32 {
+	fsc := float64(d.fsc())
+	return clamp24(float64(d.raw()-d.ofc()) * fsc / NominalFSC)
+}
+
+func clamp24(code float64) int32 {
+	code = math.Round(code)
+	if code > 1<<23-1 {
+		return 1<<23 - 1
+	}
+	if code < -(1 << 23) {
+		return -(1 << 23)
+	}
+	return int32(code)
+}
+
+// appendData appends the current conversion result, most significant byte first. When STATUS ORDER
+// is set the bits within each byte are shifted out least significant first.
+func (d *Device) appendData(out []byte) []byte {
+	v := uint32(d.data)
+	b := [3]byte{byte(v >> 16), byte(v >> 8), byte(v)}
+	if d.regs[ads1256.REG_STATUS]&ads1256.STATUS_ORDER != 0 {
+		for i := range b {
+			b[i] = bits.Reverse8(b[i])
+		}
+	}
+	return append(out, b[:]...)
+}
+
+var _ ads1256.SerialInterface = (*Device)(nil)
@@ -0,0 +1,164 @@
+package ads1256sim
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yunginnanet/ftdi-ads1256/pkg/ads1256"
+)
+
+func xfer(t *testing.T, d *Device, out []byte, read uint) []byte {
+	t.Helper()
+	if err := d.SetCS(false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := d.Write(out, false, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var in []byte
+	if read > 0 {
+		var err error
+		if in, err = d.Read(read, false, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := d.SetCS(true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return in
+}
+
+func TestRegisters(t *testing.T) {
+	t.Run("ResetValues", func(t *testing.T) {
+		d := New()
+		in := xfer(t, d, []byte{ads1256.CMD_RREG, 0x04}, 5)
+		expected := []byte{ChipID<<4 | ads1256.STATUS_DRDY, 0x01, 0x20, 0xF0, 0xE0}
+		for i := range expected {
+			if in[i] != expected[i] {
+				t.Errorf("register 0x%02X: expected 0x%02X, got 0x%02X", i, expected[i], in[i])
+			}
+		}
+	})
+
+	t.Run("WriteReadBack", func(t *testing.T) {
+		d := New()
+		xfer(t, d, []byte{ads1256.CMD_WREG | ads1256.REG_MUX, 0x01, 0x23, 0x05}, 0)
+		in := xfer(t, d, []byte{ads1256.CMD_RREG | ads1256.REG_MUX, 0x01}, 2)
+		if in[0] != 0x23 || in[1] != 0x05 {
+			t.Errorf("expected [0x23 0x05], got % X", in)
+		}
+	})
+
+	t.Run("StatusReadOnlyBits", func(t *testing.T) {
+		d := New()
+		xfer(t, d, []byte{ads1256.CMD_WREG | ads1256.REG_STATUS, 0x00, 0xFF}, 0)
+		if got := d.Register(ads1256.REG_STATUS); got != ChipID<<4|0x0F {
+			t.Errorf("expected 0x%02X, got 0x%02X", ChipID<<4|0x0F, got)
+		}
+	})
+
+	t.Run("IgnoredWhileDeselected", func(t *testing.T) {
+		d := New()
+		if _, err := d.Write([]byte{ads1256.CMD_WREG | ads1256.REG_MUX, 0x00, 0x45}, false, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := d.Register(ads1256.REG_MUX); got != 0x01 {
+			t.Errorf("expected 0x01, got 0x%02X", got)
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		d := New()
+		xfer(t, d, []byte{ads1256.CMD_WREG | ads1256.REG_DRATE, 0x00, 0x23}, 0)
+		xfer(t, d, []byte{ads1256.CMD_RESET}, 0)
+		if got := d.Register(ads1256.REG_DRATE); got != 0xF0 {
+			t.Errorf("expected 0xF0, got 0x%02X", got)
+		}
+	})
+}
+
+func TestConversions(t *testing.T) {
+	t.Run("RDATA", func(t *testing.T) {
+		d := New()
+		d.SetInput(ads1256.CH_AIN2, 1.25)
+		xfer(t, d, []byte{ads1256.CMD_WREG | ads1256.REG_MUX, 0x00, 0x28}, 0)
+		if err := d.WaitDRDY(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		in := xfer(t, d, []byte{ads1256.CMD_RDATA}, 3)
+		if got := ads1256.Convert24To32(in); got != 1<<21 {
+			t.Errorf("expected %d, got %d", 1<<21, got)
+		}
+		if d.Register(ads1256.REG_STATUS)&ads1256.STATUS_DRDY == 0 {
+			t.Error("expected DRDY to be high after reading data")
+		}
+	})
+
+	t.Run("Clamped", func(t *testing.T) {
+		d := New()
+		d.SetInput(ads1256.CH_AIN0, 10)
+		if err := d.WaitDRDY(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		in := xfer(t, d, []byte{ads1256.CMD_RDATA}, 3)
+		if got := ads1256.Convert24To32(in); got != 1<<23-1 {
+			t.Errorf("expected %d, got %d", 1<<23-1, got)
+		}
+	})
+
+	t.Run("RDATAC", func(t *testing.T) {
+		d := New()
+		d.SetInput(ads1256.CH_AIN0, -2.5)
+		xfer(t, d, []byte{ads1256.CMD_RDATAC}, 0)
+		if !d.Continuous() {
+			t.Fatal("expected continuous mode")
+		}
+		for i := 0; i < 3; i++ {
+			if err := d.WaitDRDY(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			in := xfer(t, d, nil, 3)
+			if got := ads1256.Convert24To32(in); got != -(1 << 22) {
+				t.Errorf("expected %d, got %d", -(1 << 22), got)
+			}
+		}
+		xfer(t, d, []byte{ads1256.CMD_SDATAC}, 0)
+		if d.Continuous() {
+			t.Error("expected continuous mode to be stopped")
+		}
+	})
+
+	t.Run("Standby", func(t *testing.T) {
+		d := New()
+		xfer(t, d, []byte{ads1256.CMD_STANDBY}, 0)
+		if err := d.WaitDRDY(context.Background()); !errors.Is(err, ErrNoConversion) {
+			t.Errorf("expected ErrNoConversion, got %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := d.WaitDRDY(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+		xfer(t, d, []byte{ads1256.CMD_WAKEUP}, 0)
+		if err := d.WaitDRDY(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("SelfCalibration", func(t *testing.T) {
+		d := New()
+		d.SetOffsetError(1000)
+		d.SetGainError(0.5)
+		d.SetInput(ads1256.CH_AIN0, 1.25)
+		xfer(t, d, []byte{ads1256.CMD_SELFCAL}, 0)
+		if err := d.WaitDRDY(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		in := xfer(t, d, []byte{ads1256.CMD_RDATA}, 3)
+		if got := ads1256.Convert24To32(in); got < 1<<21-2 || got > 1<<21+2 {
+			t.Errorf("expected ~%d, got %d", 1<<21, got)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package filter
+
+import (
+	"fmt"
+	"math"
+)
+
+// ButterworthQ is the Q of a second order Butterworth (maximally flat) response.
+const ButterworthQ = math.Sqrt2 / 2
+
+// DefaultNotchQ is the Q used for notch filters when none is given: at 50 Hz the notch is
+// 5 Hz wide, enough to follow the line frequency.
+const DefaultNotchQ = 10
+
+// Line frequencies for [NewNotch].
+const (
+	Line50Hz = 50.0
+	Line60Hz = 60.0
+)
+
+// Biquad is a second order IIR filter, in transposed direct form II. The coefficients follow
+// the Audio EQ Cookbook (R. Bristow-Johnson).
+//
+// The filter starts in the steady state for its first value, so a DC input does not cause
+// a start-up transient.
+type Biquad struct {
+	b0, b1, b2 float64
+	a1, a2     float64 // Normalised so that a0 = 1
+	dcGain     float64
+
+	z1, z2 float64
+	primed bool
+}
+
+// NewLowPass returns a low-pass filter with cutoff fc and quality q for values sampled at fs.
+// Use [ButterworthQ] for a flat pass band.
+func NewLowPass(fs, fc, q float64) (*Biquad, error) {
+	return newBiquad("low-pass", fs, fc, q, func(cos, alpha float64) (b0, b1, b2 float64) {
+		return (1 - cos) / 2, 1 - cos, (1 - cos) / 2
+	})
+}
+
+// NewHighPass returns a high-pass filter with cutoff fc and quality q for values sampled at fs.
+// Use [ButterworthQ] for a flat pass band.
+func NewHighPass(fs, fc, q float64) (*Biquad, error) {
+	return newBiquad("high-pass", fs, fc, q, func(cos, alpha float64) (b0, b1, b2 float64) {
+		return (1 + cos) / 2, -(1 + cos), (1 + cos) / 2
+	})
+}
+
+// NewBandPass returns a band-pass filter centred on f0 with unity gain there, and a
+// bandwidth of f0/q, for values sampled at fs.
+func NewBandPass(fs, f0, q float64) (*Biquad, error) {
+	return newBiquad("band-pass", fs, f0, q, func(cos, alpha float64) (b0, b1, b2 float64) {
+		return alpha, 0, -alpha
+	})
+}
+
+// NewNotch returns a notch filter rejecting f0, e.g. [Line50Hz] or [Line60Hz], with a
+// bandwidth of f0/q, for values sampled at fs. fs must be more than twice f0.
+func NewNotch(fs, f0, q float64) (*Biquad, error) {
+	return newBiquad("notch", fs, f0, q, func(cos, alpha float64) (b0, b1, b2 float64) {
+		return 1, -2 * cos, 1
+	})
+}
+
+func newBiquad(kind string, fs, f0, q float64, numerator func(cos, alpha float64) (b0, b1, b2 float64)) (*Biquad, error) {
+	if err := checkFrequency(fs, f0); err != nil {
+		return nil, fmt.Errorf("%s: %w", kind, err)
+	}
+	if !(q > 0) {
+		return nil, fmt.Errorf("%s: %w: Q %g", kind, ErrInvalidParameter, q)
+	}
+
+	w0 := 2 * math.Pi * f0 / fs
+	cos := math.Cos(w0)
+	alpha := math.Sin(w0) / (2 * q)
+	a0 := 1 + alpha
+
+	b0, b1, b2 := numerator(cos, alpha)
+	f := &Biquad{
+		b0: b0 / a0, b1: b1 / a0, b2: b2 / a0,
+		a1: -2 * cos / a0, a2: (1 - alpha) / a0,
+	}
+	f.dcGain = (f.b0 + f.b1 + f.b2) / (1 + f.a1 + f.a2)
+	return f, nil
+}
+
+func (f *Biquad) Process(x float64) (float64, bool) {
+	if !f.primed {
+		// steady state for a constant input x
+		y := f.dcGain * x
+		f.z1 = y - f.b0*x
+		f.z2 = f.b2*x - f.a2*y
+		f.primed = true
+	}
+	y := f.b0*x + f.z1
+	f.z1 = f.b1*x - f.a1*y + f.z2
+	f.z2 = f.b2*x - f.a2*y
+	return y, true
+}
+
+func (f *Biquad) Reset() {
+	f.z1, f.z2, f.primed = 0, 0, false
+}
+
+// Response returns the gain of the filter at frequency freq for values sampled at fs.
+func (f *Biquad) Response(fs, freq float64) float64 {
+	w := 2 * math.Pi * freq / fs
+	z1 := complex(math.Cos(w), -math.Sin(w)) // e^-jw
+	z2 := z1 * z1
+	num := complex(f.b0, 0) + complex(f.b1, 0)*z1 + complex(f.b2, 0)*z2
+	den := 1 + complex(f.a1, 0)*z1 + complex(f.a2, 0)*z2
+	return cmplxAbs(num / den)
+}
+
+func cmplxAbs(c complex128) float64 {
+	return math.Hypot(real(c), imag(c))
+}
@@ -0,0 +1,204 @@
+// Package filter provides composable digital filters for ADS1256 conversion results, a
+// [Pipeline] that runs them per channel pair on the samples produced by a channel scan, and a
+// [Resampler] that brings those samples onto a uniform timeline shared by all pairs.
+//
+// Every filter processes one channel. IIR and biquad filters need the rate at which that
+// channel is sampled, not the DRATE setting itself. Only a fixed-rate scan has a known rate,
+// 1/[ads1256.ScanOptions.Period]; in a back-to-back scan, mux settling and transfers make it
+// lower than the data rate divided among the scanned pairs.
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrInvalidParameter is returned when a filter is constructed with a parameter it cannot use.
+var ErrInvalidParameter = errors.New("invalid filter parameter")
+
+// Filter processes the values of one channel, one at a time.
+type Filter interface {
+	// Process feeds x into the filter. ok is false while the filter has no output for x,
+	// e.g. between the outputs of a decimating [Average].
+	Process(x float64) (y float64, ok bool)
+	// Reset discards all state, as if no value had been processed.
+	Reset()
+}
+
+// Chain runs filters in series. A value that a stage does not produce an output for does not
+// reach the following stages.
+type Chain []Filter
+
+func (c Chain) Process(x float64) (float64, bool) {
+	for _, f := range c {
+		var ok bool
+		if x, ok = f.Process(x); !ok {
+			return 0, false
+		}
+	}
+	return x, true
+}
+
+func (c Chain) Reset() {
+	for _, f := range c {
+		f.Reset()
+	}
+}
+
+// Average averages blocks of N values and outputs one value per block (oversampling).
+// Averaging N values of uncorrelated noise reduces it by √N at 1/N of the rate.
+type Average struct {
+	n     int
+	sum   float64
+	count int
+}
+
+// NewAverage returns an averaging decimator over n values.
+func NewAverage(n int) (*Average, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("%w: average over %d values", ErrInvalidParameter, n)
+	}
+	return &Average{n: n}, nil
+}
+
+func (a *Average) Process(x float64) (float64, bool) {
+	a.sum += x
+	a.count++
+	if a.count < a.n {
+		return 0, false
+	}
+	y := a.sum / float64(a.count)
+	a.sum, a.count = 0, 0
+	return y, true
+}
+
+func (a *Average) Reset() {
+	a.sum, a.count = 0, 0
+}
+
+// MovingAverage outputs the mean of the last N values, or of all values until N have been seen.
+type MovingAverage struct {
+	window []float64
+	next   int // index in window of the oldest value
+	count  int
+	sum    float64
+}
+
+// NewMovingAverage returns a moving average over n values.
+func NewMovingAverage(n int) (*MovingAverage, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("%w: moving average over %d values", ErrInvalidParameter, n)
+	}
+	return &MovingAverage{window: make([]float64, n)}, nil
+}
+
+func (m *MovingAverage) Process(x float64) (float64, bool) {
+	if m.count == len(m.window) {
+		m.sum -= m.window[m.next]
+	} else {
+		m.count++
+	}
+	m.window[m.next] = x
+	m.sum += x
+	m.next = (m.next + 1) % len(m.window)
+	return m.sum / float64(m.count), true
+}
+
+func (m *MovingAverage) Reset() {
+	m.next, m.count, m.sum = 0, 0, 0
+}
+
+// Median outputs the median of the last N values, or of all values until N have been seen.
+// A spike shorter than half the window does not reach the output at all.
+type Median struct {
+	window []float64
+	next   int
+	count  int
+	sorted []float64
+}
+
+// NewMedian returns a median filter over n values. Odd n avoid averaging the two middle values.
+func NewMedian(n int) (*Median, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("%w: median over %d values", ErrInvalidParameter, n)
+	}
+	return &Median{window: make([]float64, n), sorted: make([]float64, 0, n)}, nil
+}
+
+func (m *Median) Process(x float64) (float64, bool) {
+	if m.count == len(m.window) {
+		// drop the oldest value from the sorted copy
+		i, _ := slices.BinarySearch(m.sorted, m.window[m.next])
+		m.sorted = slices.Delete(m.sorted, i, i+1)
+	} else {
+		m.count++
+	}
+	m.window[m.next] = x
+	m.next = (m.next + 1) % len(m.window)
+
+	i, _ := slices.BinarySearch(m.sorted, x)
+	m.sorted = slices.Insert(m.sorted, i, x)
+
+	n := len(m.sorted)
+	if n%2 == 1 {
+		return m.sorted[n/2], true
+	}
+	return (m.sorted[n/2-1] + m.sorted[n/2]) / 2, true
+}
+
+func (m *Median) Reset() {
+	m.next, m.count = 0, 0
+	m.sorted = m.sorted[:0]
+}
+
+// IIR is a single-pole low-pass filter (exponential smoothing): y += α·(x - y).
+// It starts at the first value instead of at zero.
+type IIR struct {
+	alpha  float64
+	y      float64
+	primed bool
+}
+
+// NewIIR returns a single-pole low-pass filter with smoothing factor alpha, 0 < alpha ≤ 1.
+// Smaller values smooth more; 1 passes values through.
+func NewIIR(alpha float64) (*IIR, error) {
+	if !(alpha > 0 && alpha <= 1) {
+		return nil, fmt.Errorf("%w: IIR alpha %g", ErrInvalidParameter, alpha)
+	}
+	return &IIR{alpha: alpha}, nil
+}
+
+// NewIIRCutoff returns a single-pole low-pass filter with a -3 dB frequency of fc for values
+// sampled at fs.
+func NewIIRCutoff(fs, fc float64) (*IIR, error) {
+	if err := checkFrequency(fs, fc); err != nil {
+		return nil, err
+	}
+	return NewIIR(1 - math.Exp(-2*math.Pi*fc/fs))
+}
+
+func (f *IIR) Process(x float64) (float64, bool) {
+	if !f.primed {
+		f.y, f.primed = x, true
+		return x, true
+	}
+	f.y += f.alpha * (x - f.y)
+	return f.y, true
+}
+
+func (f *IIR) Reset() {
+	f.y, f.primed = 0, false
+}
+
+// checkFrequency checks that f can be represented at sample rate fs.
+func checkFrequency(fs, f float64) error {
+	if !(fs > 0) {
+		return fmt.Errorf("%w: sample rate %g Hz", ErrInvalidParameter, fs)
+	}
+	if !(f > 0 && f < fs/2) {
+		return fmt.Errorf("%w: %g Hz is not between 0 and the Nyquist frequency %g Hz", ErrInvalidParameter, f, fs/2)
+	}
+	return nil
+}
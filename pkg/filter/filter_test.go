@@ -0,0 +1,277 @@
+package filter
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/yunginnanet/ftdi-ads1256/pkg/ads1256"
+)
+
+func process(f Filter, xs ...float64) (ys []float64) {
+	for _, x := range xs {
+		if y, ok := f.Process(x); ok {
+			ys = append(ys, y)
+		}
+	}
+	return ys
+}
+
+func equal(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFilters(t *testing.T) {
+	avg, _ := NewAverage(3)
+	ma, _ := NewMovingAverage(3)
+	med, _ := NewMedian(3)
+	iir, _ := NewIIR(0.5)
+
+	tests := []struct {
+		name string
+		f    Filter
+		in   []float64
+		want []float64
+	}{
+		{"Average", avg, []float64{1, 2, 3, 4, 5, 6, 7}, []float64{2, 5}},
+		{"MovingAverage", ma, []float64{3, 6, 9, 12}, []float64{3, 4.5, 6, 9}},
+		{"Median", med, []float64{1, 100, 2, 3, -50, 4}, []float64{1, 50.5, 2, 3, 2, 3}},
+		{"IIR", iir, []float64{4, 8, 8}, []float64{4, 6, 7}},
+		{"Chain", Chain{must(NewAverage(2)), must(NewMovingAverage(2))}, []float64{1, 3, 5, 7, 9, 11}, []float64{2, 4, 8}},
+	}
+	for _, tt := range tests {
+		if got := process(tt.f, tt.in...); !equal(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+		tt.f.Reset()
+		if got := process(tt.f, tt.in...); !equal(got, tt.want) {
+			t.Errorf("%s after Reset: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	for _, err := range []error{
+		second(NewAverage(0)),
+		second(NewMedian(-1)),
+		second(NewIIR(0)),
+		second(NewLowPass(100, 50, ButterworthQ)),
+		second(NewNotch(100, 60, DefaultNotchQ)),
+		second(NewBandPass(1000, 10, 0)),
+	} {
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter, got %v", err)
+		}
+	}
+}
+
+func must(f Filter, err error) Filter {
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func second(_ Filter, err error) error {
+	return err
+}
+
+// amplitude returns the peak amplitude of the output of f for a sine wave of freq at fs,
+// after the filter has settled.
+func amplitude(f Filter, fs, freq float64) float64 {
+	peak := 0.0
+	for i := 0; i < int(fs)*2; i++ {
+		y, _ := f.Process(math.Sin(2 * math.Pi * freq * float64(i) / fs))
+		if i > int(fs) {
+			peak = max(peak, math.Abs(y))
+		}
+	}
+	return peak
+}
+
+func TestBiquad(t *testing.T) {
+	const fs = 1000.0
+
+	tests := []struct {
+		name  string
+		f     *Biquad
+		freq  float64
+		gain  float64
+		delta float64
+	}{
+		{"LowPass/Pass", must2(NewLowPass(fs, 10, ButterworthQ)), 1, 1, 0.01},
+		{"LowPass/Cutoff", must2(NewLowPass(fs, 10, ButterworthQ)), 10, math.Sqrt2 / 2, 0.01},
+		{"LowPass/Stop", must2(NewLowPass(fs, 10, ButterworthQ)), 200, 0, 0.01},
+		{"HighPass/Stop", must2(NewHighPass(fs, 50, ButterworthQ)), 1, 0, 0.01},
+		{"HighPass/Pass", must2(NewHighPass(fs, 50, ButterworthQ)), 300, 1, 0.02},
+		{"BandPass/Centre", must2(NewBandPass(fs, 50, 2)), 50, 1, 0.01},
+		{"BandPass/Stop", must2(NewBandPass(fs, 50, 2)), 400, 0, 0.1},
+		{"Notch/50Hz", must2(NewNotch(fs, Line50Hz, DefaultNotchQ)), 50, 0, 0.01},
+		{"Notch/60Hz", must2(NewNotch(fs, Line60Hz, DefaultNotchQ)), 60, 0, 0.01},
+		{"Notch/Pass", must2(NewNotch(fs, Line50Hz, DefaultNotchQ)), 5, 1, 0.01},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Response(fs, tt.freq); math.Abs(got-tt.gain) > tt.delta {
+			t.Errorf("%s: expected response %.3f at %g Hz, got %.3f", tt.name, tt.gain, tt.freq, got)
+		}
+		tt.f.Reset()
+		if got := amplitude(tt.f, fs, tt.freq); math.Abs(got-tt.gain) > tt.delta {
+			t.Errorf("%s: expected amplitude %.3f at %g Hz, got %.3f", tt.name, tt.gain, tt.freq, got)
+		}
+	}
+
+	// no start-up transient for a DC input
+	lp := must2(NewLowPass(fs, 10, ButterworthQ))
+	for i, y := range process(lp, 2.5, 2.5, 2.5) {
+		if math.Abs(y-2.5) > 1e-9 {
+			t.Errorf("LowPass: output %d is %g for a DC input of 2.5", i, y)
+		}
+	}
+}
+
+func must2(f *Biquad, err error) *Biquad {
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func TestParse(t *testing.T) {
+	factory, rate, err := Parse("avg:2, median:3, notch:50, lp:20:0.5", 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rate != 500 {
+		t.Errorf("expected an output rate of 500 Hz, got %g", rate)
+	}
+	chain, ok := factory().(Chain)
+	if !ok || len(chain) != 4 {
+		t.Fatalf("expected a chain of 4 filters, got %#v", factory())
+	}
+	if _, ok = chain[2].(*Biquad); !ok {
+		t.Errorf("expected a notch filter, got %T", chain[2])
+	}
+	if factory().(Chain)[0] == chain[0] {
+		t.Error("expected a new chain per call")
+	}
+
+	if factory, _, err = Parse("", 1000); factory != nil || err != nil {
+		t.Errorf("expected no filter for an empty spec, got %v", err)
+	}
+
+	for _, tt := range []struct {
+		spec string
+		fs   float64
+	}{
+		{"avg", 1000},
+		{"avg:1.5", 1000},
+		{"bogus:1", 1000},
+		{"lp:x", 1000},
+		{"notch:50", 100},          // at the Nyquist frequency
+		{"avg:10,notch:50", 1000},  // decimated to 100 SPS
+		{"median:3,iir:1.5", 1000}, // alpha out of range
+	} {
+		if _, _, err = Parse(tt.spec, tt.fs); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("%q: expected ErrInvalidParameter, got %v", tt.spec, err)
+		}
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	factory, _, err := Parse("avg:2", 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p := NewPipeline(factory)
+
+	sample := func(pair ads1256.ChannelPair, seq uint64, code int32, pga byte) ads1256.Sample {
+		return ads1256.Sample{
+			Pair:     pair,
+			Time:     time.Unix(int64(seq), 0),
+			Seq:      seq,
+			Code:     code,
+			Value:    ads1256.Voltage{Volts: float64(code) / 1000},
+			PGA:      pga,
+			DataRate: ads1256.DRATE_DR_1000_SPS,
+		}
+	}
+
+	// with carries settings on the pair, as the driver does for pairs with their own settings
+	with := func(s ads1256.Sample, settings ads1256.AcquisitionSettings) ads1256.Sample {
+		s.Pair.Settings = &settings
+		return s
+	}
+	pga1 := ads1256.AcquisitionSettings{PGA: ads1256.ADCON_PGA_1, DataRate: ads1256.DRATE_DR_1000_SPS}
+	pga8 := ads1256.AcquisitionSettings{PGA: ads1256.ADCON_PGA_8, DataRate: ads1256.DRATE_DR_1000_SPS}
+	sensor, buffered := pga1, pga8
+	sensor.SensorDetect = ads1256.ADCON_SDCS_2uA
+	buffered.BufferEn = true
+
+	var out []ads1256.Sample
+	onSample := p.OnSample(func(s ads1256.Sample) { out = append(out, s) })
+	for _, s := range []ads1256.Sample{
+		sample(ain0, 1, 100, ads1256.ADCON_PGA_1),
+		sample(ain1, 2, 1000, ads1256.ADCON_PGA_1),
+		sample(ain0, 3, 201, ads1256.ADCON_PGA_1),  // ain0: (100+201)/2
+		sample(ain1, 4, 3000, ads1256.ADCON_PGA_1), // ain1: (1000+3000)/2
+		sample(ain0, 5, 500, ads1256.ADCON_PGA_1),
+		sample(ain0, 6, 7000, ads1256.ADCON_PGA_8), // PGA changed, 500 is discarded
+		sample(ain0, 7, 9000, ads1256.ADCON_PGA_8), // ain0: (7000+9000)/2
+		with(sample(ain1, 8, 5000, ads1256.ADCON_PGA_1), pga1),
+		with(sample(ain1, 9, 100, ads1256.ADCON_PGA_1), sensor),  // sensor detect on, 5000 is discarded
+		with(sample(ain1, 10, 300, ads1256.ADCON_PGA_1), sensor), // ain1: (100+300)/2
+		with(sample(ain0, 11, 1, ads1256.ADCON_PGA_8), pga8),
+		with(sample(ain0, 12, 4000, ads1256.ADCON_PGA_8), buffered), // buffer on, 1 is discarded
+		with(sample(ain0, 13, 6000, ads1256.ADCON_PGA_8), buffered), // ain0: (4000+6000)/2
+	} {
+		onSample(s)
+	}
+
+	want := []struct {
+		seq  uint64
+		code int32
+	}{{3, 151}, {4, 2000}, {7, 8000}, {10, 200}, {13, 5000}}
+	if len(out) != len(want) {
+		t.Fatalf("expected %d samples, got %d: %v", len(want), len(out), out)
+	}
+	for i, w := range want {
+		if out[i].Seq != w.seq || out[i].Code != w.code || math.Abs(out[i].Value.Volts-(float64(out[i].Code)/1000)) > 0.001 {
+			t.Errorf("expected #%d with code %d, got %s", w.seq, w.code, out[i])
+		}
+		if out[i].Value.Overrange || out[i].Value.Underrange {
+			t.Errorf("expected #%d in range, got %s", w.seq, out[i])
+		}
+	}
+
+	t.Run("Clipped", func(t *testing.T) {
+		factory, _, err := Parse("avg:3", 1000)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p := NewPipeline(factory)
+		clipped := sample(ain0, 2, ads1256.CodeMax, ads1256.ADCON_PGA_1)
+		clipped.Value.Overrange = true
+
+		for _, s := range []ads1256.Sample{sample(ain0, 1, 100, ads1256.ADCON_PGA_1), clipped} {
+			if _, ok := p.Process(s); ok {
+				t.Fatalf("unexpected output for #%d", s.Seq)
+			}
+		}
+		// the block holds a clipped sample in the middle
+		if out, ok := p.Process(sample(ain0, 3, 100, ads1256.ADCON_PGA_1)); !ok || !out.Value.Overrange || out.Value.Underrange {
+			t.Errorf("expected an overrange output, got %s (ok: %t)", out, ok)
+		}
+		for seq := uint64(4); seq <= 6; seq++ {
+			if out, ok := p.Process(sample(ain0, seq, 100, ads1256.ADCON_PGA_1)); ok && out.Value.Overrange {
+				t.Errorf("expected the next block in range, got %s", out)
+			}
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse builds a [Factory] for a chain of filters described by spec, for values sampled at fs.
+// spec is a comma separated list of stages, applied in order:
+//
+//	avg:N        average blocks of N values, output one value per block
+//	ma:N         moving average over N values
+//	median:N     median over N values
+//	iir:ALPHA    single-pole low-pass with smoothing factor ALPHA
+//	lp:FC[:Q]    biquad low-pass at FC Hz (Q defaults to Butterworth)
+//	hp:FC[:Q]    biquad high-pass at FC Hz (Q defaults to Butterworth)
+//	bp:F0[:Q]    biquad band-pass around F0 Hz (Q defaults to 1)
+//	notch:F0[:Q] biquad notch at F0 Hz, e.g. notch:50 (Q defaults to DefaultNotchQ)
+//
+// Frequencies refer to fs, which a decimating avg stage divides for the stages after it; rate
+// is the resulting rate of the filtered values. An empty spec yields a nil Factory.
+func Parse(spec string, fs float64) (factory Factory, rate float64, err error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fs, nil
+	}
+
+	var stages []func() (Filter, error)
+	for _, stage := range strings.Split(spec, ",") {
+		name, args, _ := strings.Cut(strings.TrimSpace(stage), ":")
+		params, err := parseParams(args)
+		if err != nil {
+			return nil, 0, fmt.Errorf("filter stage %q: %w", stage, err)
+		}
+		newStage, err := stageFactory(name, params, fs)
+		if err != nil {
+			return nil, 0, fmt.Errorf("filter stage %q: %w", stage, err)
+		}
+		// check the parameters once, so that the factory cannot fail
+		if _, err = newStage(); err != nil {
+			return nil, 0, fmt.Errorf("filter stage %q: %w", stage, err)
+		}
+		stages = append(stages, newStage)
+		if name == "avg" {
+			fs /= params[0]
+		}
+	}
+
+	return func() Filter {
+		chain := make(Chain, len(stages))
+		for i, newStage := range stages {
+			chain[i], _ = newStage()
+		}
+		return chain
+	}, fs, nil
+}
+
+func parseParams(args string) ([]float64, error) {
+	if args == "" {
+		return nil, nil
+	}
+	var params []float64
+	for _, arg := range strings.Split(args, ":") {
+		v, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidParameter, arg)
+		}
+		params = append(params, v)
+	}
+	return params, nil
+}
+
+func stageFactory(name string, params []float64, fs float64) (func() (Filter, error), error) {
+	// param returns the i-th parameter, or def if it was not given
+	param := func(i int, def float64) float64 {
+		if i < len(params) {
+			return params[i]
+		}
+		return def
+	}
+	count := func() (int, error) {
+		if len(params) != 1 || params[0] != float64(int(params[0])) {
+			return 0, fmt.Errorf("%w: expected a count", ErrInvalidParameter)
+		}
+		return int(params[0]), nil
+	}
+	if len(params) == 0 {
+		return nil, fmt.Errorf("%w: missing parameter", ErrInvalidParameter)
+	}
+
+	switch name {
+	case "avg":
+		n, err := count()
+		return func() (Filter, error) { return NewAverage(n) }, err
+	case "ma":
+		n, err := count()
+		return func() (Filter, error) { return NewMovingAverage(n) }, err
+	case "median":
+		n, err := count()
+		return func() (Filter, error) { return NewMedian(n) }, err
+	case "iir":
+		return func() (Filter, error) { return NewIIR(params[0]) }, nil
+	case "lp":
+		return func() (Filter, error) { return NewLowPass(fs, params[0], param(1, ButterworthQ)) }, nil
+	case "hp":
+		return func() (Filter, error) { return NewHighPass(fs, params[0], param(1, ButterworthQ)) }, nil
+	case "bp":
+		return func() (Filter, error) { return NewBandPass(fs, params[0], param(1, 1)) }, nil
+	case "notch":
+		return func() (Filter, error) { return NewNotch(fs, params[0], param(1, DefaultNotchQ)) }, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown filter %q", ErrInvalidParameter, name)
+	}
+}
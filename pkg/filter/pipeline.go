@@ -0,0 +1,103 @@
+package filter
+
+import (
+	"math"
+	"sync"
+
+	"github.com/yunginnanet/ftdi-ads1256/pkg/ads1256"
+)
+
+// Factory returns a new filter, with fresh state, for one channel pair.
+type Factory func() Filter
+
+// Pipeline filters the samples of a channel scan, running a separate filter for every channel
+// pair. A pair's filter is reset when the acquisition settings its samples were taken with
+// change, since the values before and after are not comparable.
+//
+// Filters run on both the code and the voltage of a sample; the output sample carries the
+// filtered values and the time and sequence numbers of the input sample that produced it. A
+// range flag set on any input since the previous output is set on the output, so that a
+// decimating filter does not hide a clipped input.
+type Pipeline struct {
+	factory Factory
+
+	mu    sync.Mutex
+	chans map[byte]*channel // keyed by MUX value
+}
+
+// channel is the filter state of one channel pair.
+type channel struct {
+	code, volts Filter
+	settings    ads1256.AcquisitionSettings
+	over, under bool // range flags of the inputs since the last output
+}
+
+// NewPipeline returns a pipeline that creates the filters for each channel pair with factory.
+func NewPipeline(factory Factory) *Pipeline {
+	return &Pipeline{factory: factory, chans: make(map[byte]*channel)}
+}
+
+// Process feeds s into the filter of its channel pair. ok is false if the filter has no
+// output for s.
+func (p *Pipeline) Process(s ads1256.Sample) (out ads1256.Sample, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	mux := ads1256.Mux{Pos: s.Pair.Pos, Neg: s.Pair.Neg}.Byte()
+	ch, found := p.chans[mux]
+	if !found {
+		ch = &channel{code: p.factory(), volts: p.factory(), settings: settingsOf(s)}
+		p.chans[mux] = ch
+	}
+	if settings := settingsOf(s); settings != ch.settings {
+		ch.code.Reset()
+		ch.volts.Reset()
+		ch.settings = settings
+		ch.over, ch.under = false, false
+	}
+	ch.over = ch.over || s.Value.Overrange
+	ch.under = ch.under || s.Value.Underrange
+
+	code, ok := ch.code.Process(float64(s.Code))
+	volts, _ := ch.volts.Process(s.Value.Volts)
+	if !ok {
+		return ads1256.Sample{}, false
+	}
+	out = s
+	out.Code = toCode(code)
+	out.Value.Volts = volts
+	out.Value.Overrange, out.Value.Underrange = ch.over, ch.under
+	ch.over, ch.under = false, false
+	return out, true
+}
+
+// settingsOf returns the acquisition settings s was taken with. Samples of pairs without
+// settings of their own only carry the PGA and data rate from the configuration.
+func settingsOf(s ads1256.Sample) ads1256.AcquisitionSettings {
+	if s.Pair.Settings != nil {
+		return *s.Pair.Settings
+	}
+	return ads1256.AcquisitionSettings{PGA: s.PGA, DataRate: s.DataRate}
+}
+
+// toCode rounds a filtered code and clamps it to the range of conversion results.
+func toCode(code float64) int32 {
+	return int32(max(min(math.Round(code), float64(ads1256.CodeMax)), float64(ads1256.CodeMin)))
+}
+
+// OnSample returns a callback for [ads1256.ScanOptions.OnSample] that passes the filtered
+// samples on to next.
+func (p *Pipeline) OnSample(next ads1256.SampleCallback) ads1256.SampleCallback {
+	return func(s ads1256.Sample) {
+		if out, ok := p.Process(s); ok {
+			next(out)
+		}
+	}
+}
+
+// Reset discards the filter state of every channel pair.
+func (p *Pipeline) Reset() {
+	p.mu.Lock()
+	clear(p.chans)
+	p.mu.Unlock()
+}
@@ -0,0 +1,268 @@
+package filter
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/yunginnanet/ftdi-ads1256/pkg/ads1256"
+)
+
+// maxPendingFrames is the number of incomplete frames a [Resampler] holds before it drops the
+// oldest, so that a channel pair that stops producing samples does not grow them without bound.
+const maxPendingFrames = 64
+
+// Frame is one instant of the uniform timeline of a [Resampler], with a sample of every channel
+// pair taken at Time.
+type Frame struct {
+	Index   uint64           // Frame number (starts at 1)
+	Time    time.Time        // Shared by all samples of the frame
+	Samples []ads1256.Sample // One per channel pair, in the order given to NewResampler
+}
+
+// FrameCallback receives every complete [Frame] of a [Resampler].
+type FrameCallback func(f Frame)
+
+// Resampler converts the samples of a multiplexed scan, which arrive one pair at a time at
+// irregular times, into frames at a uniform rate in which every pair shares one timestamp.
+//
+// The timeline starts at the first sample after every pair has been seen. When decimating
+// (the output rate is below the input rate of a pair), values are first low-pass filtered to
+// a quarter of the output rate to prevent aliasing; the filter delays them accordingly. The
+// value of each pair at a frame time is then interpolated linearly between the samples on
+// either side of it. Samples taken with different acquisition settings are not interpolated
+// between: the frame holds the newer value, and the anti-aliasing filter of the pair restarts.
+//
+// Output samples carry the frame time, the frame index as ChanSeq and Cycle, their own Seq
+// numbering, and the settings of the newer input sample. A range flag set on either input
+// sample is set on the output.
+type Resampler struct {
+	rate      float64
+	pairs     []ads1256.ChannelPair
+	antiAlias Factory // nil when not decimating
+
+	mu      sync.Mutex
+	tracks  map[byte]*track // keyed by MUX value
+	start   time.Time
+	started bool
+	base    uint64   // frame number, from 0, of pending[0]
+	pending []*frame // incomplete frames
+	seq     uint64
+	dropped uint64
+}
+
+// track is the resampling state of one channel pair.
+type track struct {
+	index       int // in Resampler.pairs
+	code, volts Filter
+	settings    ads1256.AcquisitionSettings
+	last        point
+	seen        bool
+	next        uint64 // frame number of the next frame to fill
+}
+
+// point is a filtered input sample.
+type point struct {
+	sample      ads1256.Sample
+	code, volts float64
+}
+
+type frame struct {
+	samples []ads1256.Sample
+	filled  int
+}
+
+// NewResampler returns a resampler that outputs frames of pairs at rate Hz, from samples of
+// each pair arriving at about fs Hz. Use a fixed-rate scan, for which fs is
+// 1/[ads1256.ScanOptions.Period]; in a back-to-back scan the rate of each pair is not known in
+// advance, and an estimate misplaces the anti-aliasing filter.
+func NewResampler(rate, fs float64, pairs ...ads1256.ChannelPair) (*Resampler, error) {
+	if !(rate > 0) || !(fs > 0) {
+		return nil, fmt.Errorf("%w: resampling from %g Hz to %g Hz", ErrInvalidParameter, fs, rate)
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("%w: no channel pairs to resample", ErrInvalidParameter)
+	}
+
+	r := &Resampler{
+		rate:      rate,
+		pairs:     append([]ads1256.ChannelPair(nil), pairs...),
+		antiAlias: antiAlias(fs, rate),
+		tracks:    make(map[byte]*track, len(pairs)),
+	}
+	for i, pair := range pairs {
+		mux := ads1256.Mux{Pos: pair.Pos, Neg: pair.Neg}.Byte()
+		if _, dup := r.tracks[mux]; dup {
+			return nil, fmt.Errorf("%w: channel pair %s/%s given twice", ErrInvalidParameter, pair.Pos, pair.Neg)
+		}
+		r.tracks[mux] = &track{index: i}
+	}
+	r.Reset()
+	return r, nil
+}
+
+// antiAlias returns a factory for a fourth order Butterworth low-pass at a quarter of rate, for
+// values sampled at fs, or nil if rate is not below fs.
+func antiAlias(fs, rate float64) Factory {
+	if rate >= fs {
+		return nil
+	}
+	fc := rate / 4
+	return func() Filter {
+		// the Q of the two sections of a fourth order Butterworth response
+		lp1, _ := NewLowPass(fs, fc, 1/(2*math.Cos(math.Pi/8)))
+		lp2, _ := NewLowPass(fs, fc, 1/(2*math.Cos(3*math.Pi/8)))
+		return Chain{lp1, lp2}
+	}
+}
+
+// Process feeds s into the resampler and returns the frames it completed, in order. Samples of
+// pairs the resampler was not created with are ignored.
+func (r *Resampler) Process(s ads1256.Sample) []Frame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tr, found := r.tracks[ads1256.Mux{Pos: s.Pair.Pos, Neg: s.Pair.Neg}.Byte()]
+	if !found {
+		return nil
+	}
+
+	settings := settingsOf(s)
+	step := tr.seen && settings != tr.settings
+	if step {
+		tr.code.Reset()
+		tr.volts.Reset()
+	}
+	tr.settings = settings
+
+	cur := point{sample: s, code: float64(s.Code), volts: s.Value.Volts}
+	cur.code, _ = tr.code.Process(cur.code)
+	cur.volts, _ = tr.volts.Process(cur.volts)
+
+	prev := cur
+	if tr.seen {
+		prev = tr.last
+	}
+	tr.last, tr.seen = cur, true
+
+	if !r.started {
+		for _, other := range r.tracks {
+			if !other.seen {
+				return nil
+			}
+		}
+		r.start, r.started = s.Time, true
+	}
+
+	r.fill(tr, prev, cur, step)
+	return r.complete()
+}
+
+// fill interpolates the value of tr for every frame up to the time of cur.
+func (r *Resampler) fill(tr *track, prev, cur point, step bool) {
+	for {
+		t := r.frameTime(tr.next)
+		if t.After(cur.sample.Time) {
+			return
+		}
+		if f := r.frame(tr.next); f != nil {
+			f.samples[tr.index] = interpolate(prev, cur, t, step)
+			f.filled++
+		}
+		tr.next++
+	}
+}
+
+// frameTime returns the time of frame n, counting from 0.
+func (r *Resampler) frameTime(n uint64) time.Time {
+	return r.start.Add(time.Duration(math.Round(float64(n) / r.rate * float64(time.Second))))
+}
+
+// frame returns pending frame n, adding frames as needed, or nil if it was dropped.
+func (r *Resampler) frame(n uint64) *frame {
+	if n < r.base {
+		return nil
+	}
+	for r.base+uint64(len(r.pending)) <= n {
+		r.pending = append(r.pending, &frame{samples: make([]ads1256.Sample, len(r.pairs))})
+	}
+	for len(r.pending) > maxPendingFrames {
+		r.pending = r.pending[1:]
+		r.base++
+		r.dropped++
+	}
+	if n < r.base {
+		return nil
+	}
+	return r.pending[n-r.base]
+}
+
+// complete removes the complete frames at the head of the pending frames and returns them.
+func (r *Resampler) complete() (frames []Frame) {
+	for len(r.pending) > 0 && r.pending[0].filled == len(r.pairs) {
+		f := Frame{Index: r.base + 1, Time: r.frameTime(r.base), Samples: r.pending[0].samples}
+		for i := range f.Samples {
+			r.seq++
+			f.Samples[i].Seq = r.seq
+			f.Samples[i].ChanSeq, f.Samples[i].Cycle = f.Index, f.Index
+		}
+		frames = append(frames, f)
+		r.pending = r.pending[1:]
+		r.base++
+	}
+	return frames
+}
+
+// interpolate returns the sample between prev and cur at t, prev.Time < t <= cur.Time, or cur
+// if they cannot be interpolated between.
+func interpolate(prev, cur point, t time.Time, step bool) ads1256.Sample {
+	out := cur.sample
+	out.Time = t
+	code, volts := cur.code, cur.volts
+	if span := cur.sample.Time.Sub(prev.sample.Time); !step && span > 0 {
+		w := float64(t.Sub(prev.sample.Time)) / float64(span)
+		code = prev.code + w*(cur.code-prev.code)
+		volts = prev.volts + w*(cur.volts-prev.volts)
+		out.Value.Overrange = prev.sample.Value.Overrange || cur.sample.Value.Overrange
+		out.Value.Underrange = prev.sample.Value.Underrange || cur.sample.Value.Underrange
+	}
+	out.Code = toCode(code)
+	out.Value.Volts = volts
+	return out
+}
+
+// OnSample returns a callback for [ads1256.ScanOptions.OnSample] that passes the completed
+// frames on to next.
+func (r *Resampler) OnSample(next FrameCallback) ads1256.SampleCallback {
+	return func(s ads1256.Sample) {
+		for _, f := range r.Process(s) {
+			next(f)
+		}
+	}
+}
+
+// Dropped returns the number of frames that were dropped because a channel pair fell more
+// than maxPendingFrames behind.
+func (r *Resampler) Dropped() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.dropped
+}
+
+// Reset discards all state and restarts the timeline with the next samples.
+func (r *Resampler) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newFilter := r.antiAlias
+	if newFilter == nil {
+		newFilter = func() Filter { return Chain(nil) }
+	}
+	for _, tr := range r.tracks {
+		*tr = track{index: tr.index, code: newFilter(), volts: newFilter()}
+	}
+	r.start, r.started = time.Time{}, false
+	r.base, r.pending = 0, nil
+	r.seq, r.dropped = 0, 0
+}
@@ -0,0 +1,160 @@
+package filter
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/yunginnanet/ftdi-ads1256/pkg/ads1256"
+)
+
+var (
+	ain0 = ads1256.ChannelPair{Pos: ads1256.CH_AIN0, Neg: ads1256.CH_AINCOM}
+	ain1 = ads1256.ChannelPair{Pos: ads1256.CH_AIN1, Neg: ads1256.CH_AINCOM}
+)
+
+// scan feeds r a multiplexed scan of ain0 and ain1 at fs per pair, ain1 lagging by half a
+// period, with the value of each pair at time t given by signal.
+func scan(r *Resampler, fs float64, n int, signal func(pair ads1256.ChannelPair, t float64) float64) (frames []Frame) {
+	epoch := time.Now()
+	period := time.Duration(float64(time.Second) / fs)
+	for i := range n {
+		for j, pair := range []ads1256.ChannelPair{ain0, ain1} {
+			at := time.Duration(i)*period + time.Duration(j)*period/2
+			v := signal(pair, at.Seconds())
+			frames = append(frames, r.Process(ads1256.Sample{
+				Pair:     pair,
+				Time:     epoch.Add(at),
+				Code:     int32(v * 1000),
+				Value:    ads1256.Voltage{Volts: v},
+				PGA:      ads1256.ADCON_PGA_1,
+				DataRate: ads1256.DRATE_DR_1000_SPS,
+			})...)
+		}
+	}
+	return frames
+}
+
+func TestResampler(t *testing.T) {
+	t.Run("Interpolate", func(t *testing.T) {
+		r, err := NewResampler(300, 100, ain0, ain1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// a ramp is interpolated exactly
+		ramp := func(pair ads1256.ChannelPair, t float64) float64 { return t + float64(pair.Pos) }
+		frames := scan(r, 100, 20, ramp)
+		if len(frames) < 50 {
+			t.Fatalf("expected about 57 frames, got %d", len(frames))
+		}
+
+		first := frames[0].Time
+		for i, f := range frames {
+			if f.Index != uint64(i+1) {
+				t.Fatalf("expected frame %d, got %d", i+1, f.Index)
+			}
+			if want := first.Add(time.Duration(i) * time.Second / 300); f.Time.Sub(want).Abs() > time.Microsecond {
+				t.Errorf("frame %d: expected time %s after the first, got %s", f.Index, want.Sub(first), f.Time.Sub(first))
+			}
+			for j, s := range f.Samples {
+				if s.Pair != []ads1256.ChannelPair{ain0, ain1}[j] || !s.Time.Equal(f.Time) || s.Cycle != f.Index {
+					t.Errorf("frame %d: unexpected sample %d: %s at %s", f.Index, j, s, s.Time)
+				}
+				if want := ramp(s.Pair, f.Time.Sub(first).Seconds()+0.005); math.Abs(s.Value.Volts-want) > 1e-6 {
+					t.Errorf("frame %d: expected %s at %.6fV, got %s", f.Index, s.Pair.Pos, want, s.Value)
+				}
+			}
+		}
+	})
+
+	t.Run("AntiAlias", func(t *testing.T) {
+		r, err := NewResampler(10, 100, ain0, ain1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// without anti-aliasing, 45 Hz at 10 Hz aliases to 5 Hz at full amplitude
+		noisy := func(_ ads1256.ChannelPair, t float64) float64 { return 1 + 0.5*math.Sin(2*math.Pi*45*t) }
+		frames := scan(r, 100, 1000, noisy)
+		if len(frames) < 95 {
+			t.Fatalf("expected about 100 frames, got %d", len(frames))
+		}
+		for _, f := range frames[10:] {
+			for _, s := range f.Samples {
+				if math.Abs(s.Value.Volts-1) > 0.005 {
+					t.Errorf("frame %d: expected 1V, got %s", f.Index, s.Value)
+				}
+			}
+		}
+	})
+
+	t.Run("Settings", func(t *testing.T) {
+		r, err := NewResampler(100, 100, ain0, ain1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// ain0 steps from 0V to 1V when its buffer is turned on, which is not interpolated across
+		buffered := ads1256.AcquisitionSettings{PGA: ads1256.ADCON_PGA_1, DataRate: ads1256.DRATE_DR_1000_SPS, BufferEn: true}
+		epoch := time.Now()
+		var frames []Frame
+		for i := range 10 {
+			s := ads1256.Sample{Pair: ain0, Time: epoch.Add(time.Duration(i) * 10 * time.Millisecond), PGA: ads1256.ADCON_PGA_1, DataRate: ads1256.DRATE_DR_1000_SPS}
+			if i >= 5 {
+				s.Pair.Settings = &buffered
+				s.Value.Volts = 1
+			}
+			frames = append(frames, r.Process(s)...)
+			frames = append(frames, r.Process(ads1256.Sample{Pair: ain1, Time: s.Time.Add(5 * time.Millisecond), PGA: s.PGA, DataRate: s.DataRate})...)
+		}
+		if len(frames) < 8 {
+			t.Fatalf("expected about 9 frames, got %d", len(frames))
+		}
+		for _, f := range frames {
+			if v := f.Samples[0].Value.Volts; v != 0 && v != 1 {
+				t.Errorf("frame %d: expected 0V or 1V, got %s", f.Index, f.Samples[0].Value)
+			}
+		}
+		if last := frames[len(frames)-1].Samples[0]; last.Value.Volts != 1 || last.Pair.Settings == nil || !last.Pair.Settings.BufferEn {
+			t.Errorf("expected the buffered value in the last frame, got %s", last)
+		}
+	})
+
+	t.Run("Stalled", func(t *testing.T) {
+		r, err := NewResampler(100, 100, ain0, ain1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		stalled := func(_ ads1256.ChannelPair, _ float64) float64 { return 0 }
+		if frames := scan(r, 100, 10, stalled); len(frames) == 0 {
+			t.Fatal("expected frames")
+		}
+
+		epoch := time.Now().Add(time.Second)
+		for i := range 200 {
+			r.Process(ads1256.Sample{Pair: ain0, Time: epoch.Add(time.Duration(i) * 10 * time.Millisecond)})
+		}
+		if r.Dropped() == 0 {
+			t.Error("expected frames to be dropped while ain1 is stalled")
+		}
+
+		r.Reset()
+		if r.Dropped() != 0 {
+			t.Error("expected Reset to clear the dropped count")
+		}
+	})
+
+	for _, err := range []error{
+		resamplerErr(NewResampler(0, 100, ain0)),
+		resamplerErr(NewResampler(10, 0, ain0)),
+		resamplerErr(NewResampler(10, 100)),
+		resamplerErr(NewResampler(10, 100, ain0, ain0)),
+	} {
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter, got %v", err)
+		}
+	}
+}
+
+func resamplerErr(_ *Resampler, err error) error {
+	return err
+}
@@ -1,6 +1,7 @@
 package ft232h
 
 import (
+	"context"
 	"fmt"
 	"github.com/ardnew/ft232h"
 	"time"
@@ -10,18 +11,22 @@ func (ft *FT232H) SetDRDY(pin uint) error {
 	ft.drdyPin = ft232h.CPin(pin)
 	time.Sleep(5 * time.Millisecond)
 	fmt.Printf("drdy set: %s, pos: %d\n", ft.drdyPin.String(), ft.drdyPin.Pos())
-	return ft.GPIO.ConfigPin(ft.drdyPin, ft232h.Input, false)
+	return pinError("configure", "DRDY", ft.drdyPin, ft.GPIO.ConfigPin(ft.drdyPin, ft232h.Input, false))
 }
 
 func (ft *FT232H) DRDYPin() ft232h.CPin {
 	return ft.drdyPin
 }
 
-func (ft *FT232H) WaitDRDY() error {
+// WaitDRDY polls the DRDY pin until it goes low. It gives up with ctx.Err() once ctx is done.
+func (ft *FT232H) WaitDRDY(ctx context.Context) error {
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		hl, err := ft.FT232H.GPIO.Get(ft.drdyPin)
 		if err != nil {
-			return fmt.Errorf("failed to read DRDY pin: %w", err)
+			return pinError("get", "DRDY", ft.drdyPin, err)
 		}
 		if !hl {
 			break
@@ -35,7 +40,7 @@ func (ft *FT232H) SetPWDN(pin uint) error {
 	ft.pwdnPin = ft232h.CPin(pin)
 	time.Sleep(5 * time.Millisecond)
 	fmt.Printf("pwdn set: %s, pos: %d\n", ft.pwdnPin.String(), ft.pwdnPin.Pos())
-	return ft.GPIO.ConfigPin(ft.pwdnPin, ft232h.Output, false)
+	return pinError("configure", "PWDN", ft.pwdnPin, ft.GPIO.ConfigPin(ft.pwdnPin, ft232h.Output, false))
 }
 
 func (ft *FT232H) PWDNPin() ft232h.CPin {
@@ -44,28 +49,22 @@ func (ft *FT232H) PWDNPin() ft232h.CPin {
 
 func (ft *FT232H) PowerDown() error {
 	if ft.pwdnPin == 0 {
-		return fmt.Errorf("PWDN pin not set")
-	}
-	if err := ft.FT232H.GPIO.Set(ft.pwdnPin, false); err != nil {
-		return fmt.Errorf("failed to set PWDN pin: %w", err)
+		return pinError("set", "PWDN", 0, ErrPinNotSet)
 	}
-	return nil
+	return pinError("set", "PWDN", ft.pwdnPin, ft.FT232H.GPIO.Set(ft.pwdnPin, false))
 }
 
 func (ft *FT232H) PowerUp() error {
 	if ft.pwdnPin == 0 {
-		return fmt.Errorf("PWDN pin not set")
+		return pinError("set", "PWDN", 0, ErrPinNotSet)
 	}
-	if err := ft.FT232H.GPIO.Set(ft.pwdnPin, true); err != nil {
-		return fmt.Errorf("failed to set PWDN pin: %w", err)
-	}
-	return nil
+	return pinError("set", "PWDN", ft.pwdnPin, ft.FT232H.GPIO.Set(ft.pwdnPin, true))
 }
 
 func (ft *FT232H) SetCSPin(pin uint) error {
 	ft.csPin = ft232h.CPin(pin)
 	fmt.Printf("cs set: %s, pos: %d\n", ft.csPin.String(), ft.csPin.Pos())
-	return ft.GPIO.ConfigPin(ft.csPin, ft232h.Output, false)
+	return pinError("configure", "CS", ft.csPin, ft.GPIO.ConfigPin(ft.csPin, ft232h.Output, false))
 }
 
 func (ft *FT232H) CSPin() ft232h.CPin {
@@ -73,7 +72,7 @@ func (ft *FT232H) CSPin() ft232h.CPin {
 }
 
 func (ft *FT232H) SetCS(high bool) error {
-	return ft.FT232H.GPIO.Set(ft.csPin, high)
+	return pinError("set", "CS", ft.csPin, ft.FT232H.GPIO.Set(ft.csPin, high))
 }
 
 func (ft *FT232H) Read(count uint, start bool, stop bool) ([]byte, error) {
@@ -1,5 +1,46 @@
 package ft232h
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
 
-var ErrBadDescriptor = fmt.Errorf("invalid FT232H descriptor provided")
+	"github.com/ardnew/ft232h"
+)
+
+var (
+	// ErrBadDescriptor is returned when a [Descriptor] does not identify a device.
+	ErrBadDescriptor = errors.New("invalid FT232H descriptor provided")
+	// ErrTooManyDescriptors is returned when [ConnectFT232h] is given more than one [Descriptor].
+	ErrTooManyDescriptors = errors.New("at most one FT232H descriptor may be provided")
+	// ErrPinNotSet is returned when a pin is used before it has been assigned, e.g. PowerDown
+	// before SetPWDN. It means the wiring is misconfigured; retrying will not help.
+	ErrPinNotSet = errors.New("pin not set")
+)
+
+// PinError records a failed operation on one of the GPIO pins wired to the ADS1256.
+// Err is [ErrPinNotSet], or the error from the FT232H, which usually means a USB failure.
+type PinError struct {
+	Op   string      // "configure", "get" or "set"
+	Name string      // Function of the pin: "DRDY", "PWDN" or "CS"
+	Pin  ft232h.CPin // Zero if not set
+	Err  error
+}
+
+func (e *PinError) Error() string {
+	if errors.Is(e.Err, ErrPinNotSet) {
+		return fmt.Sprintf("%s %s pin: %v", e.Op, e.Name, e.Err)
+	}
+	return fmt.Sprintf("%s %s pin %s: %v", e.Op, e.Name, e.Pin, e.Err)
+}
+
+func (e *PinError) Unwrap() error {
+	return e.Err
+}
+
+// pinError wraps a non-nil err in a [PinError].
+func pinError(op, name string, pin ft232h.CPin, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PinError{Op: op, Name: name, Pin: pin, Err: err}
+}
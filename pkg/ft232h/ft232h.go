@@ -1,9 +1,6 @@
 package ft232h
 
-import (
-	"fmt"
-	"github.com/ardnew/ft232h"
-)
+import "github.com/ardnew/ft232h"
 
 func ConnectFT232h(choice ...Descriptor) (ft *FT232H, err error) {
 	ft = &FT232H{}
@@ -15,14 +12,14 @@ func ConnectFT232h(choice ...Descriptor) (ft *FT232H, err error) {
 	case 1:
 		desc := choice[0]
 		if err = choice[0].Validate(); err != nil {
-			return nil, ErrBadDescriptor
+			return nil, err
 		}
 		ft.FT232H, err = ft232h.OpenMask(desc.Mask())
 		if err == nil && ft.FT232H != nil {
 			ft.info = ft.Info()
 		}
 	default:
-		return nil, fmt.Errorf("invalid number of arguments")
+		return nil, ErrTooManyDescriptors
 	}
 
 	return ft, err
@@ -1,6 +1,7 @@
 package ft232h
 
 import (
+	"errors"
 	"github.com/ardnew/ft232h"
 	"os"
 	"strconv"
@@ -169,3 +170,28 @@ func TestConnectFT232h(t *testing.T) {
 	})
 
 }
+
+func TestErrors(t *testing.T) {
+	t.Run("PinNotSet", func(t *testing.T) {
+		ft := &FT232H{}
+		for _, err := range []error{ft.PowerDown(), ft.PowerUp()} {
+			var pinErr *PinError
+			if !errors.As(err, &pinErr) || !errors.Is(err, ErrPinNotSet) {
+				t.Fatalf("expected a PinError, got %v", err)
+			}
+			if pinErr.Name != "PWDN" || pinErr.Op != "set" {
+				t.Errorf("unexpected %+v", pinErr)
+			}
+		}
+	})
+	t.Run("TooManyDescriptors", func(t *testing.T) {
+		if _, err := ConnectFT232h(ByIndex(0), ByIndex(1)); !errors.Is(err, ErrTooManyDescriptors) {
+			t.Errorf("expected ErrTooManyDescriptors, got %v", err)
+		}
+	})
+	t.Run("BadDescriptor", func(t *testing.T) {
+		if _, err := ConnectFT232h(ByIndex(-1)); !errors.Is(err, ErrBadDescriptor) {
+			t.Errorf("expected ErrBadDescriptor, got %v", err)
+		}
+	})
+}